@@ -0,0 +1,657 @@
+// Combination is a tool to generate combinations from a list of grouping data (sets) and prints them to stdout or a file.
+//
+// It takes the sets, one per line, on stdin or a file and prints the combinations to stdout or a file. The output generated is valid Go syntax, as long as the set name and values are.
+//
+// A set follows the syntax:
+//
+//	name: value value ...
+//
+// The value list is much like a list of arguments in a shell:
+// it is space-separated, and "non-safe" strings must be quoted.
+//
+// Unquoted values can be ranges, expanded to all their values:
+// 1..10, 0..100..10 with a step, 'a'..'f' or 0x00..0xFF..0x10, which keeps the base of the bounds.
+//
+// Unquoted values can also hold shell-like brace expressions, expanded to each alternative:
+// user-{admin,guest}, {GET,POST}{,/} or v{1..3}. Literal braces and commas are escaped: []int\{1\,2\}.
+//
+// Blank lines are skipped, and # or // starts a comment up to the end of the line,
+// unless it's quoted or inside a value. A line ending with \ continues on the next line.
+//
+// For example, the sets:
+//
+//	card: "Heart Red" Tile Clover "Pike Black"
+//	figure: Jack Queen King
+//
+// would generate the following test table:
+//
+//	{card: "Heart Red", figure: "Jack"},
+//	{card: "Heart Red", figure: "Queen"},
+//	{card: "Heart Red", figure: "King"},
+//	{card: "Tile", figure: "Jack"},
+//	{card: "Tile", figure: "Queen"},
+//	{card: "Tile", figure: "King"},
+//	{card: "Clover", figure: "Jack"},
+//	{card: "Clover", figure: "Queen"},
+//	{card: "Clover", figure: "King"},
+//	{card: "Pike Black", figure: "Jack"},
+//	{card: "Pike Black", figure: "Queen"},
+//	{card: "Pike Black", figure: "King"},
+//
+// In a shell:
+//
+//	cat << EOF | combination
+//	card: "Heart Red" Tile Clover "Pike Black"
+//	figure: Jack Queen King
+//	EOF
+//
+// Sets can also be read from JSON, YAML or TOML with -input-format, or a .json, .yaml, .yml or .toml file:
+// each top-level key is the name of a set, and its array holds the values.
+// Strings are taken as is, and other scalars are written as Go literals:
+//
+//	card: ["\"Heart Red\"", Tile, Clover]
+//	figure: [Jack, Queen, King]
+//	n: [1, 2.5, true]
+//
+// Lines starting with ! are rules, which leave out the combinations they don't allow:
+//
+//	!exclude card=="Heart Red" && figure==Jack
+//	!require card==Tile -> figure!=King
+//
+// The ! can be left out before a colon, e.g require: card==Tile -> figure!=King.
+// Their values must be values of the sets, written the same way. See comb.Rule for their syntax.
+//
+// With -emit table, the rows are wrapped in the declaration of a test table,
+// with one field per set, and written with go/format:
+//
+//	tests := []struct {
+//	    card   string
+//	    figure string
+//	}{
+//	    {card: "Heart Red", figure: "Jack"},
+//	    ...
+//	}
+//
+// Field types are inferred from the values (string, int, float64, rune, bool),
+// or given after the name of the set:
+//
+//	status int: http.StatusOK http.StatusNotFound
+//
+// The values of a set with a type must be Go expressions, and the literals among them must fit the type,
+// so that a value like 4.2 for an int is reported with its line and column.
+// With the type string, unquoted values are strings, and quoted ones are Go expressions:
+//
+//	port int: 80 443 8080
+//	name string: alice bob "http.MethodGet"
+//
+// With -emit testfile, a whole test file is written, with a test function running a subtest for each row:
+//
+//	combination -sets cards.sets -emit testfile -pkg cards -func TestCardFigure -o cards_test.go
+//
+// With -update, the rows between markers in a Go file are regenerated from the sets file named by the begin marker,
+// so that test tables are kept in sync with their sets, e.g with go generate:
+//
+//	//go:generate combination -update $GOFILE
+//
+//	var tests = []struct {
+//	    card   string
+//	    figure string
+//	}{
+//	    // combination:begin sets=cards.sets
+//	    {card: "Heart Red", figure: "Jack"},
+//	    ...
+//	    // combination:end
+//	}
+//
+// With -format json or jsonl, the combinations are written as a JSON array of objects, or one object per line.
+// Values which are Go literals are decoded to JSON strings, numbers and booleans; others are kept as strings.
+//
+// With -format csv or tsv, the combinations are written as records after a header with the set names.
+// The flag -unquote writes the values which are Go strings unquoted, e.g Heart Red instead of "Heart Red".
+//
+// With -format markdown or html, the combinations are written as a table with the set names as columns,
+// and -indices adds a first column with their index.
+//
+// With -format fuzzcorpus, each combination is written as a file of the seed corpus of the fuzz target -fuzz-func,
+// in testdata/fuzz/FuzzName of the directory -o or else the current one, with one argument per set:
+//
+//	combination -sets parse.sets -format fuzzcorpus -fuzz-func FuzzParse
+//
+// The arguments are of the type of their set, which must be string, []byte, int, float64 or bool.
+//
+// With -template, the combinations are written with a text/template of any shape; see comb.ParseTemplate.
+//
+// The extract command reads an existing test table from a Go file, and writes the sets of the distinct values of its fields:
+//
+//	combination extract -file cards_test.go -var tests -o cards.sets
+//
+// The coverage command checks that a test table holds every combination of the sets, and writes the missing ones.
+// With -strength, it checks that every tuple of values is in one of its rows, and writes the missing tuples.
+// It exits with status 1 if anything is missing:
+//
+//	combination coverage -sets cards.sets -file cards_test.go -var tests
+//
+// The count command writes the number of values of each set and the exact number of combinations, without generating them,
+// and with rules, the number of combinations each one excludes:
+//
+//	combination count -sets cards.sets
+//
+// Combinations are numbered from 0 in the order they are written.
+// The flag -index writes only the combination at an index, unless rules exclude it, and -rank writes the index of a combination:
+//
+//	combination -sets cards.sets -index 4
+//	combination -sets cards.sets -rank '{card: "Tile", figure: "Queen"}'
+//
+// With -sample N, only N combinations picked at random are written; -seed S picks them again, and -indices writes their index:
+//
+//	combination -sets cards.sets -sample 3 -seed 42 -indices
+//
+// When the full product is too big, -strength writes fewer combinations
+// such that each pair (-strength 2), triple (-strength 3), ... of values from different sets is in at least one of them.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/vincent-petithory/combination/comb"
+)
+
+var (
+	srcp        string
+	inFormat    string
+	destp       string
+	emit        string
+	outFormat   string
+	unquote     bool
+	templatep   string
+	varName     string
+	pkgName     string
+	funcName    string
+	fuzzFunc    string
+	index       int
+	rank        string
+	strength    int
+	sample      int
+	seed        int64
+	withIndices bool
+	update      string
+)
+
+func init() {
+	flag.StringVar(&srcp, "sets", "-", "read sets from this file, or stdin if -")
+	flag.StringVar(&inFormat, "input-format", "", "read sets as lines (sets), JSON (json), YAML (yaml) or TOML (toml); guessed from the extension of -sets if empty")
+	flag.StringVar(&destp, "o", "-", "write combinations to this file, or stdout if -")
+	flag.StringVar(&outFormat, "format", "go", "write the combinations as Go (go), a JSON array (json), JSON Lines (jsonl), CSV (csv), TSV (tsv), a Markdown table (markdown), an HTML table (html) or the seed corpus of a fuzz target (fuzzcorpus)")
+	flag.BoolVar(&unquote, "unquote", false, "unquote the values which are Go strings with -format csv or tsv")
+	flag.StringVar(&templatep, "template", "", "write the combinations with the text/template in this file, instead of -format")
+	flag.StringVar(&emit, "emit", "rows", "write the combinations as rows of a test table (rows), the full declaration of a test table (table) or a test file (testfile)")
+	flag.StringVar(&varName, "var", "tests", "name of the test table declared with -emit table or testfile")
+	flag.StringVar(&pkgName, "pkg", "main", "package of the test file written with -emit testfile")
+	flag.StringVar(&funcName, "func", "TestCombinations", "name of the test function written with -emit testfile")
+	flag.StringVar(&fuzzFunc, "fuzz-func", "", "name of the fuzz target whose seed corpus is written with -format fuzzcorpus")
+	flag.IntVar(&index, "index", -1, "write only the combination at this index, starting at 0, or fail if rules exclude it")
+	flag.IntVar(&strength, "strength", 0, "write only enough combinations to cover every tuple of this many values from different sets, e.g 2 for pairwise")
+	flag.IntVar(&sample, "sample", 0, "write only this many combinations, picked at random")
+	flag.Int64Var(&seed, "seed", 1, "seed of the random picks of -sample; the same seed gives the same combinations")
+	flag.BoolVar(&withIndices, "indices", false, "write the index of each combination in a comment after it, or in the first column with -format markdown or html")
+	flag.StringVar(&update, "update", "", "regenerate the rows between the combination:begin and combination:end markers of this Go file")
+	flag.StringVar(&rank, "rank", "", "write the index of this combination, e.g '{card: \"Heart\", figure: \"Jack\"}'")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `combination [flags]
+combination extract [flags]
+combination coverage [flags]
+combination count [flags]
+
+  combination is a tool to generate combinations from a list of grouping data (sets)
+  It takes the sets, one per line, on stdin or a file and prints the combinations to stdout or a file.
+  The output generated is valid Go syntax, as long as the set name and values are.
+
+ A set follows the syntax:
+
+     name: value value ...
+
+ The value list is much like a list of arguments in a shell:
+ it is space-separated, and "non-safe" strings must be quoted.
+
+ Unquoted values can be ranges, expanded to all their values:
+ 1..10, 0..100..10 with a step, 'a'..'f' or 0x00..0xFF..0x10, which keeps the base of the bounds.
+
+ Unquoted values can also hold shell-like brace expressions, expanded to each alternative:
+ user-{admin,guest}, {GET,POST}{,/} or v{1..3}. Literal braces and commas are escaped: []int\{1\,2\}.
+
+ Blank lines are skipped, and # or // starts a comment up to the end of the line,
+ unless it's quoted or inside a value. A line ending with \ continues on the next line.
+
+ For example, the sets:
+
+     card: "Heart Red" Tile Clover "Pike Black"
+     figure: Jack Queen King
+
+ In a shell:
+
+     cat << EOF | combination
+     card: "Heart Red" Tile Clover "Pike Black"
+     figure: Jack Queen King
+     EOF
+
+ Sets can also be read from JSON, YAML or TOML with -input-format, or from
+ a .json, .yaml, .yml or .toml file: each top-level key is the name of a set,
+ and its array holds the values.
+
+ Lines starting with ! are rules, which leave out the combinations they don't allow:
+
+     !exclude card=="Heart Red" && figure==Jack
+     !require card==Tile -> figure!=King
+
+ The ! can be left out before a colon, e.g require: card==Tile -> figure!=King.
+ Comparisons (== or =, !=) are combined with !, &&, || and -> (implication).
+ Their values must be values of the sets, written the same way.
+
+ Use -emit table to write the declaration of the test table around the rows.
+ Field types are inferred from the values, or given after the set name:
+
+     status int: http.StatusOK http.StatusNotFound
+
+ Values are then checked against the type. With the type string,
+ unquoted values are strings, e.g name string: alice bob.
+
+ Use -emit testfile to write a test file with a test function running
+ a subtest for each row; see -pkg and -func.
+
+ Use -update file.go to regenerate the rows between the markers
+
+     // combination:begin sets=cards.sets
+     // combination:end
+
+ in file.go, from the sets file named by the begin marker.
+
+ Use -format json or jsonl to write the combinations as a JSON array of objects
+ or one JSON object per line, with the set names as keys.
+
+ Use -format csv or tsv to write the combinations as records after a header
+ with the set names, and -unquote to unquote the values which are Go strings.
+
+ Use -format markdown or html to write the combinations as a table,
+ and -indices to number its rows.
+
+ Use -format fuzzcorpus -fuzz-func FuzzName to write each combination in
+ testdata/fuzz/FuzzName, as the seed corpus of a fuzz target with one argument
+ of type string, []byte, int, float64 or bool per set, in the directory -o.
+
+ Use -template file.tmpl to write each combination with a text/template.
+ It gets a row with .Combination, .Index, .Total, .First, .Last and .Get "name",
+ and the functions quote, unquote, camel and snake. The templates named header
+ and footer, if defined, are written before and after the combinations.
+
+ Use combination extract -file file_test.go -var tests to write the sets
+ of an existing test table; see combination extract -h.
+
+ Use combination coverage -sets file -file file_test.go -var tests to write the
+ combinations missing from an existing test table; see combination coverage -h.
+
+ Use combination count -sets file to write the number of combinations without
+ generating them; see combination count -h.
+
+ Combinations are numbered from 0 in the order they are written.
+ Use -index to get a single one, unless rules exclude it, and -rank to get
+ the index of one.
+
+ Use -sample N to only write N combinations picked at random, the same ones
+ for the same -seed, and -indices to know their index.
+
+ Use -strength 2 to only write enough combinations for every pair of values
+ from different sets to be in at least one of them (pairwise), 3 for triples, etc.
+
+`)
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "extract":
+			extract(os.Args[2:])
+			return
+		case "coverage":
+			coverageCmd(os.Args[2:])
+			return
+		case "count":
+			count(os.Args[2:])
+			return
+		}
+	}
+	flag.Parse()
+
+	if update != "" {
+		if err := comb.UpdateFile(update); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	sets, rules := readSets(srcp, inFormat)
+	if outFormat == "fuzzcorpus" {
+		writeFuzzCorpus(sets, rules)
+		return
+	}
+	dest, closeDest := create(destp)
+	defer closeDest()
+
+	if rank != "" {
+		c, err := comb.ParseCombination(rank)
+		if err != nil {
+			log.Fatal(err)
+		}
+		i, err := comb.Rank(sets, c)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := fmt.Fprintln(dest, i); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	it, err := newIterator(sets, rules)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := write(dest, sets, it); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeFuzzCorpus writes the combinations selected by the flags as the seed corpus of -fuzz-func,
+// in the directory -o or else the current one. It exits if they can't be written.
+func writeFuzzCorpus(sets []comb.Set, rules []comb.Rule) {
+	if fuzzFunc == "" {
+		log.Fatal("-format fuzzcorpus needs -fuzz-func")
+	}
+	dir := "."
+	if destp != "-" {
+		dir = destp
+	}
+	it, err := newIterator(sets, rules)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := comb.WriteFuzzCorpus(filepath.Join(dir, "testdata", "fuzz", fuzzFunc), sets, it); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readSets reads the sets and rules of the file at path, or of stdin if path is -,
+// in format or the one given by the extension of path if format is empty.
+// It exits if they can't be read.
+func readSets(path, format string) ([]comb.Set, []comb.Rule) {
+	var src io.Reader
+	name := path
+	if path == "-" {
+		src = os.Stdin
+		name = "<stdin>"
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		src = f
+	}
+	if format == "" {
+		format = comb.InputFormat(path)
+	}
+	sets, rules, err := comb.ParseSetsFormat(format, src)
+	if err != nil {
+		var perr *comb.ParseError
+		if errors.As(err, &perr) {
+			perr.File = name
+		}
+		log.Fatal(err)
+	}
+	return sets, rules
+}
+
+// create creates the file at path, or returns stdout if path is -, buffered.
+// The function returned flushes the buffer and closes the file.
+// It exits if the file can't be created, written or closed.
+func create(path string) (io.Writer, func()) {
+	f := os.Stdout
+	if path != "-" {
+		var err error
+		f, err = os.Create(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	w := bufio.NewWriter(f)
+	return w, func() {
+		err := w.Flush()
+		if f != os.Stdout {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// extract runs the extract command with args.
+func extract(args []string) {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	file := fs.String("file", "", "read the test table from this Go file")
+	name := fs.String("var", "tests", "name of the test table")
+	destp := fs.String("o", "-", "write the sets to this file, or stdout if -")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, `combination extract [flags]
+
+  extract reads a test table from a Go file and writes the sets of the distinct
+  values of each of its fields, so that it can be generated with combination.
+
+`)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *file == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	src, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	combinations, types, err := comb.ReadTable(*file, src, *name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dest, closeDest := create(*destp)
+	defer closeDest()
+	if err := comb.WriteSets(dest, comb.ExtractSets(combinations, types)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// coverageCmd runs the coverage command with args.
+func coverageCmd(args []string) {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	srcp := fs.String("sets", "-", "read the sets from this file, or stdin if -")
+	inFormat := fs.String("input-format", "", "format of the sets: sets, json, yaml or toml; guessed from the extension of -sets if empty")
+	file := fs.String("file", "", "read the test table from this Go file")
+	name := fs.String("var", "tests", "name of the test table")
+	strength := fs.Int("strength", 0, "check that every tuple of this many values from different sets is covered, instead of every combination")
+	destp := fs.String("o", "-", "write the missing combinations to this file, or stdout if -")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, `combination coverage [flags]
+
+  coverage reads a test table from a Go file and writes the combinations of the
+  sets which aren't in it. With -strength, it writes the tuples of values which
+  aren't in any of its rows instead.
+  It exits with status 1 if anything is missing.
+
+`)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *file == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	sets, rules := readSets(*srcp, *inFormat)
+	src, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rows, _, err := comb.ReadTable(*file, src, *name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var missing []comb.Combination
+	if *strength > 0 {
+		missing, err = comb.MissingTuples(sets, *strength, rows, rules...)
+	} else {
+		missing, err = comb.Missing(sets, rows, rules...)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	dest, closeDest := create(*destp)
+	err = comb.WriteCombinations(dest, missing)
+	closeDest()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(missing) > 0 {
+		os.Exit(1)
+	}
+}
+
+// count runs the count command with args.
+func count(args []string) {
+	fs := flag.NewFlagSet("count", flag.ExitOnError)
+	srcp := fs.String("sets", "-", "read the sets from this file, or stdin if -")
+	inFormat := fs.String("input-format", "", "format of the sets: sets, json, yaml or toml; guessed from the extension of -sets if empty")
+	destp := fs.String("o", "-", "write the counts to this file, or stdout if -")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, `combination count [flags]
+
+  count writes the number of values of each set and the exact number of their
+  combinations, without generating them. If there are rules, it also writes the
+  number of combinations each one excludes and the number allowed by all.
+
+`)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	sets, rules := readSets(*srcp, *inFormat)
+	dest, closeDest := create(*destp)
+	defer closeDest()
+	if err := comb.WriteCount(dest, sets, rules); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newIterator returns an iterator over the combinations selected by the flags.
+func newIterator(sets []comb.Set, rules []comb.Rule) (comb.IndexedIterator, error) {
+	switch {
+	case index >= 0:
+		c, err := comb.Unrank(sets, index)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rules {
+			if !r.Allows(c) {
+				return nil, fmt.Errorf("combination %d is excluded by the rule %s", index, r.Text)
+			}
+		}
+		return comb.NewIndicesIterator(sets, []int{index}), nil
+	case strength > 0:
+		combinations, err := comb.NewCovering(sets, strength, rules...)
+		if err != nil {
+			return nil, err
+		}
+		indices := make([]int, len(combinations))
+		for i, c := range combinations {
+			if indices[i], err = comb.Rank(sets, c); err != nil {
+				return nil, err
+			}
+		}
+		return comb.NewIndicesIterator(sets, indices), nil
+	case sample > 0:
+		indices, err := comb.Sample(sets, sample, seed, rules...)
+		if err != nil {
+			return nil, err
+		}
+		return comb.NewIndicesIterator(sets, indices), nil
+	default:
+		it, err := comb.NewIterator(sets, rules...)
+		if err != nil {
+			return nil, err
+		}
+		return it, nil
+	}
+}
+
+// write writes the combinations yielded by it to w, in the format and shape selected by the flags.
+func write(w io.Writer, sets []comb.Set, it comb.IndexedIterator) error {
+	if templatep != "" {
+		b, err := os.ReadFile(templatep)
+		if err != nil {
+			return err
+		}
+		t, err := comb.ParseTemplate(templatep, string(b))
+		if err != nil {
+			return err
+		}
+		return comb.WriteTemplate(w, t, sets, it)
+	}
+	switch outFormat {
+	case "go":
+	case "json":
+		return comb.WriteJSON(w, it)
+	case "jsonl":
+		return comb.WriteJSONLines(w, it)
+	case "csv":
+		return comb.WriteCSV(w, sets, it, ',', unquote)
+	case "tsv":
+		return comb.WriteCSV(w, sets, it, '\t', unquote)
+	case "markdown":
+		return comb.WriteMarkdown(w, sets, it, withIndices)
+	case "html":
+		return comb.WriteHTML(w, sets, it, withIndices)
+	default:
+		return fmt.Errorf("unknown -format %q", outFormat)
+	}
+	switch emit {
+	case "rows":
+		return writeIterator(w, it)
+	case "table":
+		return comb.WriteTable(w, varName, sets, it)
+	case "testfile":
+		return comb.WriteTestFile(w, pkgName, funcName, varName, sets, it)
+	default:
+		return fmt.Errorf("unknown -emit %q", emit)
+	}
+}
+
+// writeIterator writes combinations with their index if -indices is set.
+func writeIterator(w io.Writer, it comb.IndexedIterator) error {
+	if withIndices {
+		return comb.WriteIndexedIterator(w, it)
+	}
+	return comb.WriteIterator(w, it)
+}
@@ -0,0 +1,77 @@
+package comb
+
+import "strings"
+
+// expandBraces returns the values of v after a shell-like brace expansion.
+//
+// A brace expression is a list of comma-separated alternatives, e.g user-{admin,guest}, or a range, e.g {1..3}.
+// It can be nested, e.g {a,b{1,2}}, and there can be several in a value, e.g {GET,POST}-{1..2},
+// which gives all their combinations. Braces with neither a comma nor a range are kept, e.g {x}.
+// The characters {, } and , are kept literally when preceded by a \, e.g []int\{1\,2\}.
+func expandBraces(v string) ([]string, error) {
+	for i := 0; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case '{':
+			alts, end, err := braceAlternatives(v, i)
+			if err != nil {
+				return nil, err
+			}
+			if alts == nil {
+				continue
+			}
+			var values []string
+			for _, alt := range alts {
+				expanded, err := expandBraces(v[:i] + alt + v[end+1:])
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, expanded...)
+			}
+			return values, nil
+		}
+	}
+	return []string{unescapeBraces(v)}, nil
+}
+
+// braceAlternatives returns the alternatives of the brace expression starting at v[start],
+// and the index of its closing brace.
+// It returns nil alternatives if v[start] doesn't start a brace expression.
+func braceAlternatives(v string, start int) ([]string, int, error) {
+	var (
+		alts  []string
+		depth int
+	)
+	from := start + 1
+	for i := from; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case ',':
+			if depth == 0 {
+				alts = append(alts, v[from:i])
+				from = i + 1
+			}
+		case '}':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if alts != nil {
+				return append(alts, v[from:i]), i, nil
+			}
+			values, err := expandRange(v[from:i])
+			return values, i, err
+		}
+	}
+	return nil, 0, nil
+}
+
+var braceUnescaper = strings.NewReplacer(`\{`, "{", `\}`, "}", `\,`, ",")
+
+func unescapeBraces(v string) string {
+	return braceUnescaper.Replace(v)
+}
@@ -0,0 +1,55 @@
+package comb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		v      string
+		values []string
+	}{
+		{v: "user-{admin,guest,anon}", values: []string{"user-admin", "user-guest", "user-anon"}},
+		{v: "{GET,POST,PUT}", values: []string{"GET", "POST", "PUT"}},
+		{v: "{a,b{1,2}}x", values: []string{"ax", "b1x", "b2x"}},
+		{v: "{a,b}{1,2}", values: []string{"a1", "a2", "b1", "b2"}},
+		{v: "/v1{,/}", values: []string{"/v1", "/v1/"}},
+		{v: "v{1..3}", values: []string{"v1", "v2", "v3"}},
+		{v: "{x}", values: []string{"{x}"}},
+		{v: "{a,b", values: []string{"{a,b"}},
+		{v: `[]int\{1\,2\}`, values: []string{"[]int{1,2}"}},
+		{v: `{\{,\}}`, values: []string{"{", "}"}},
+		{v: "T{}", values: []string{"T{}"}},
+		{v: "Heart", values: []string{"Heart"}},
+	}
+	for _, test := range tests {
+		values, err := expandBraces(test.v)
+		if err != nil {
+			t.Errorf("%s: %v", test.v, err)
+			continue
+		}
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%s: expected %#v, got %#v", test.v, test.values, values)
+		}
+	}
+	if _, err := expandBraces("{1..3..0}"); err != ErrInvalidRange {
+		t.Errorf("expected %v, got %v", ErrInvalidRange, err)
+	}
+}
+
+func TestParseSetsBraces(t *testing.T) {
+	sets, _, err := ParseSets(strings.NewReader(`user: user-{admin,guest} "{a,b}"
+id: {0x0..0x2}0`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Set{
+		{Name: "user", Values: []string{"user-admin", "user-guest", "{a,b}"}},
+		{Name: "id", Values: []string{"0x00", "0x10", "0x20"}},
+	}
+	if !reflect.DeepEqual(sets, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, sets)
+	}
+}
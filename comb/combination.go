@@ -0,0 +1,128 @@
+// Package comb generates combinations from sets of values, as rows of Go test tables.
+//
+// Sets are usually parsed from text with ParseSets, one per line, or from JSON, YAML or TOML with ParseSetsFormat:
+//
+//	sets, rules, err := comb.ParseSets(strings.NewReader(`
+//	card: "\"Heart\"" "\"Tile\""
+//	figure: "\"Jack\"" "\"Queen\""
+//	`))
+//
+// New creates all their combinations, in order, leaving out those not allowed by rules;
+// NewIterator goes through them one at a time, and NewCovering, Sample or Unrank select some of them.
+// The combinations can then be written as rows with WriteCombinations, as a whole test table with WriteTable,
+// or as JSON, CSV, Markdown, HTML or with a text/template.
+//
+// Product does the same at runtime with Go values instead of their source text, filling a struct for each combination:
+//
+//	for tt := range comb.Product[test](comb.Dim("card", []Card{Heart, Tile}), comb.Dim("n", []int{1, 2})) {
+//		...
+//	}
+//
+// Run runs a subtest for each combination, named after its values, e.g card=Heart/figure=Jack.
+//
+// The command github.com/vincent-petithory/combination/cmd/combination does all of this from the command line.
+package comb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Element is the value of a set in a combination.
+type Element struct {
+	Name  string
+	Value string
+}
+
+// ErrSetNoValues represents an error when a set contains no values.
+var ErrSetNoValues = errors.New("set has no values")
+
+// ErrSetInvalidName represents an error when a set has an empty name or an invalid string value.
+var ErrSetInvalidName = errors.New("set has an invalid name")
+
+// ErrSetNoColon represents an error when a set has no colon after its name.
+var ErrSetNoColon = errors.New("set has no colon after its name")
+
+// ErrValueNoClosingQuote represents an error when a quoted set's value has no closing quote.
+var ErrValueNoClosingQuote = errors.New("set value has no closing quote")
+
+// New creates all combinations from sets, leaving out those not allowed by rules.
+//
+// It returns the combinations or an error, ErrSetNoValues if one of the sets provided has no values,
+// an *OverflowError if there are too many combinations to index them or a *RuleError if a rule refers to an unknown set.
+//
+// All combinations are held in memory; use NewIterator to go through them one at a time.
+func New(sets []Set, rules ...Rule) ([]Combination, error) {
+	it, err := NewIterator(sets, rules...)
+	if err != nil {
+		return nil, err
+	}
+	combinations := make([]Combination, 0, it.Len())
+	for {
+		c, ok := it.Next()
+		if !ok {
+			break
+		}
+		combinations = append(combinations, c)
+	}
+	return combinations, nil
+}
+
+// Combination represents a single combination created from one or more sets.
+type Combination []Element
+
+// WriteCombinations writes all combinations to w.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteCombinations(w io.Writer, combinations []Combination) error {
+	return WriteIterator(w, SliceIterator(combinations))
+}
+
+// WriteIterator writes the combinations yielded by it to w, as they come.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteIterator(w io.Writer, it Iterator) error {
+	for {
+		c, ok := it.Next()
+		if !ok {
+			return nil
+		}
+		if err := writeCombination(w, c, ""); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteIndexedIterator writes the combinations yielded by it to w like WriteIterator,
+// each followed by a comment with its index.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteIndexedIterator(w io.Writer, it IndexedIterator) error {
+	for {
+		c, ok := it.Next()
+		if !ok {
+			return nil
+		}
+		if err := writeCombination(w, c, fmt.Sprintf(" // %d", it.Index())); err != nil {
+			return err
+		}
+	}
+}
+
+func writeCombination(w io.Writer, c Combination, comment string) error {
+	if _, err := fmt.Fprint(w, "{"); err != nil {
+		return err
+	}
+	for _, e := range c[:len(c)-1] {
+		if _, err := fmt.Fprintf(w, "%s: %s, ", e.Name, e.Value); err != nil {
+			return err
+		}
+	}
+	e := c[len(c)-1]
+	if _, err := fmt.Fprintf(w, "%s: %s", e.Name, e.Value); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, "},"+comment)
+	return err
+}
@@ -0,0 +1,168 @@
+package comb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"text/tabwriter"
+)
+
+// ErrOverflow represents an error when there are too many combinations to index them with an int.
+var ErrOverflow = errors.New("too many combinations")
+
+// OverflowError is returned when the number of combinations from sets doesn't fit in an int.
+type OverflowError struct {
+	Count *big.Int
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrOverflow, e.Count)
+}
+
+// Unwrap returns ErrOverflow.
+func (e *OverflowError) Unwrap() error {
+	return ErrOverflow
+}
+
+// Count returns the exact number of combinations from sets, before rules are applied.
+//
+// It returns an error, ErrSetNoValues if one of the sets provided has no values.
+func Count(sets []Set) (*big.Int, error) {
+	if len(sets) == 0 {
+		return new(big.Int), nil
+	}
+	n := big.NewInt(1)
+	for _, set := range sets {
+		l := len(set.Values)
+		if l == 0 {
+			return nil, ErrSetNoValues
+		}
+		n.Mul(n, big.NewInt(int64(l)))
+	}
+	return n, nil
+}
+
+// numCombinations returns the number of combinations from sets.
+//
+// It returns an error, ErrSetNoValues if one of the sets provided has no values
+// or an *OverflowError if the number doesn't fit in an int.
+func numCombinations(sets []Set) (int, error) {
+	n, err := Count(sets)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() || n.Int64() > math.MaxInt {
+		return 0, &OverflowError{Count: n}
+	}
+	return int(n.Int64()), nil
+}
+
+// CountRules returns the exact number of combinations from sets allowed by all rules,
+// and the number of combinations excluded by each of them. A combination can be excluded by more than one rule.
+//
+// No combination is created: only the values of the sets rules refer to are walked,
+// up to the ones deciding the rules, and the number of values of the other sets multiply the counts.
+//
+// It returns an error, ErrSetNoValues if one of the sets provided has no values
+// or a *RuleError if a rule refers to an unknown set or value.
+func CountRules(sets []Set, rules []Rule) (*big.Int, []*big.Int, error) {
+	total, err := Count(sets)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err = checkRules(sets, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	excluded := make([]*big.Int, len(rules))
+	for i := range rules {
+		excluded[i] = new(big.Int).Sub(total, countAllowed(sets, rules[i:i+1]))
+	}
+	return countAllowed(sets, rules), excluded, nil
+}
+
+// countAllowed returns the number of combinations from sets allowed by all rules.
+func countAllowed(sets []Set, rules []Rule) *big.Int {
+	if len(sets) == 0 {
+		return new(big.Int)
+	}
+	row := make([]int, len(sets))
+	for k := range row {
+		row[k] = -1
+	}
+	return countRow(sets, rules, row)
+}
+
+// countRow returns the number of combinations from sets allowed by all rules which have the values of row,
+// a value index per set or -1 for the values not set. row is left unchanged.
+func countRow(sets []Set, rules []Rule, row []int) *big.Int {
+	// walk the sets not set named by rules first: all rules are decided once they have a value.
+	names := ruleNames(rules)
+	var order, others []int
+	for k, set := range sets {
+		switch {
+		case row[k] != -1:
+		case names[set.Name]:
+			order = append(order, k)
+		default:
+			others = append(others, k)
+		}
+	}
+	order = append(order, others...)
+	// combinations[j] is the number of combinations of the values of the sets order[j:].
+	combinations := make([]*big.Int, len(order)+1)
+	combinations[len(order)] = big.NewInt(1)
+	for j := len(order) - 1; j > -1; j-- {
+		combinations[j] = new(big.Int).Mul(combinations[j+1], big.NewInt(int64(len(sets[order[j]].Values))))
+	}
+
+	n := new(big.Int)
+	var walk func(j int)
+	walk = func(j int) {
+		switch rowValue(sets, rules, row) {
+		case ruleFalse:
+			return
+		case ruleTrue:
+			n.Add(n, combinations[j])
+			return
+		}
+		k := order[j]
+		for v := range sets[k].Values {
+			row[k] = v
+			walk(j + 1)
+		}
+		row[k] = -1
+	}
+	walk(0)
+	return n
+}
+
+// WriteCount writes to w the number of values of each set, the number of combinations from sets
+// and, if there are rules, the number of combinations each one excludes and the number allowed by all.
+//
+// It returns an error if the combinations can't be counted or if an error occurs when writing to w.
+func WriteCount(w io.Writer, sets []Set, rules []Rule) error {
+	total, err := Count(sets)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, set := range sets {
+		fmt.Fprintf(tw, "%s\t%d\n", set.Name, len(set.Values))
+	}
+	fmt.Fprintf(tw, "total\t%s\n", total)
+	if len(rules) > 0 {
+		allowed, excluded, err := CountRules(sets, rules)
+		if err != nil {
+			_ = tw.Flush()
+			return err
+		}
+		for i, r := range rules {
+			fmt.Fprintf(tw, "%s\t-%s\n", r.Text, excluded[i])
+		}
+		fmt.Fprintf(tw, "allowed\t%s\n", allowed)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,124 @@
+package comb
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	n, err := Count(rankSets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.String() != "12" {
+		t.Errorf("expected 12, got %s", n)
+	}
+
+	sets := make([]Set, 40)
+	for i := range sets {
+		sets[i] = Set{Name: "s" + strconv.Itoa(i), Values: []string{"0", "1", "2", "3"}}
+	}
+	n, err = Count(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "1208925819614629174706176"; n.String() != expected {
+		t.Errorf("expected %s, got %s", expected, n)
+	}
+
+	_, err = New(sets)
+	var overflowErr *OverflowError
+	if !errors.As(err, &overflowErr) {
+		t.Fatalf("expected an *OverflowError, got %v", err)
+	}
+	if overflowErr.Count.Cmp(n) != 0 {
+		t.Errorf("expected a count of %s, got %s", n, overflowErr.Count)
+	}
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected %v, got %v", ErrOverflow, err)
+	}
+	if _, err := Unrank(sets, 0); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Unrank: expected %v, got %v", ErrOverflow, err)
+	}
+	if _, err := RankIndices(sets, make([]int, len(sets))); !errors.Is(err, ErrOverflow) {
+		t.Errorf("RankIndices: expected %v, got %v", ErrOverflow, err)
+	}
+}
+
+func TestCountRules(t *testing.T) {
+	var rules []Rule
+	for _, text := range []string{`!exclude S1 == "\"X\""`, `!require I3 != 0`} {
+		rule, err := ParseRule(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, rule)
+	}
+	allowed, excluded, err := CountRules(rankSets, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed.String() != "4" {
+		t.Errorf("expected 4 allowed combinations, got %s", allowed)
+	}
+	if expected := []*big.Int{big.NewInt(6), big.NewInt(4)}; !reflect.DeepEqual(excluded, expected) {
+		t.Errorf("expected %v excluded combinations, got %v", expected, excluded)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCount(&buf, rankSets, rules); err != nil {
+		t.Fatal(err)
+	}
+	expected := `S1                      2
+S2                      2
+I3                      3
+total                   12
+!exclude S1 == "\"X\""  -6
+!require I3 != 0        -4
+allowed                 4
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestCountRulesManySets(t *testing.T) {
+	sets := make([]Set, 40)
+	for i := range sets {
+		sets[i] = Set{Name: "s" + strconv.Itoa(i), Values: []string{"0", "1", "2", "3"}}
+	}
+	var rules []Rule
+	for _, text := range []string{"!require s0==0 && s1==0 && s2==0", "!exclude s2==1 || s39==3", "!exclude s0 == 0 -> s1 == 3"} {
+		rule, err := ParseRule(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, rule)
+	}
+	allowed, excluded, err := CountRules(sets, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 4^40 combinations: the first rule keeps 4^37 of them, the second one those with s2!=1 and s39!=3, 9*4^38
+	// and the third one those with s0==0 and s1!=3, 3*4^38.
+	pow4 := func(n int64, times int64) *big.Int {
+		x := new(big.Int).Exp(big.NewInt(4), big.NewInt(n), nil)
+		return x.Mul(x, big.NewInt(times))
+	}
+	expected := []*big.Int{
+		new(big.Int).Sub(pow4(40, 1), pow4(37, 1)),
+		new(big.Int).Sub(pow4(40, 1), pow4(38, 9)),
+		new(big.Int).Sub(pow4(40, 1), pow4(38, 3)),
+	}
+	if !reflect.DeepEqual(excluded, expected) {
+		t.Errorf("expected %v excluded combinations, got %v", expected, excluded)
+	}
+	// s0==0, s1==0, s2==0 and s39!=3.
+	if expected := pow4(36, 3); allowed.Cmp(expected) != 0 {
+		t.Errorf("expected %s allowed combinations, got %s", expected, allowed)
+	}
+}
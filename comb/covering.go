@@ -0,0 +1,277 @@
+package comb
+
+import "errors"
+
+// ErrInvalidStrength represents an error when a covering strength is lower than 1 or greater than the number of sets.
+var ErrInvalidStrength = errors.New("invalid covering strength")
+
+// NewCovering creates combinations from sets such that each tuple of strength values from different sets
+// is found in at least one of them. With a strength of 2, every pair of values is covered (pairwise testing).
+//
+// The combinations are usually far fewer than with New, but this isn't a minimal covering:
+// they are built greedily, each one covering as many new tuples as possible.
+// The result is the same for the same sets.
+//
+// All combinations are allowed by rules, and tuples which can't be in any allowed combination are left out.
+//
+// It returns the combinations or an error, ErrSetNoValues if one of the sets provided has no values,
+// ErrInvalidStrength if strength is out of range or a *RuleError if a rule refers to an unknown set.
+func NewCovering(sets []Set, strength int, rules ...Rule) ([]Combination, error) {
+	for _, set := range sets {
+		if len(set.Values) == 0 {
+			return nil, ErrSetNoValues
+		}
+	}
+	if strength < 1 || strength > len(sets) {
+		return nil, ErrInvalidStrength
+	}
+	rules, err := checkRules(sets, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	cov := newCoverage(sets, strength)
+	cov.rules = rules
+	var combinations []Combination
+	for {
+		row := cov.nextRow()
+		if row == nil {
+			break
+		}
+		cov.cover(row)
+		c := make(Combination, len(sets))
+		for k, set := range sets {
+			c[k] = Element{Name: set.Name, Value: set.Values[row[k]]}
+		}
+		combinations = append(combinations, c)
+	}
+	return combinations, nil
+}
+
+// tupleGroup holds which tuples of values of a group of sets are covered.
+type tupleGroup struct {
+	sets    []int
+	covered []bool
+}
+
+// tupleIndex returns the index in g.covered of the tuple of values in row, or -1 if one isn't set.
+func (g *tupleGroup) tupleIndex(sizes []int, row []int) int {
+	var i int
+	for _, k := range g.sets {
+		if row[k] == -1 {
+			return -1
+		}
+		i = i*sizes[k] + row[k]
+	}
+	return i
+}
+
+// firstUncovered returns the index of the first uncovered tuple, or -1 if all are covered.
+func (g *tupleGroup) firstUncovered() int {
+	for i, covered := range g.covered {
+		if !covered {
+			return i
+		}
+	}
+	return -1
+}
+
+// has reports whether set k is in the group.
+func (g *tupleGroup) has(k int) bool {
+	for _, gk := range g.sets {
+		if gk == k {
+			return true
+		}
+	}
+	return false
+}
+
+// coverage tracks the tuples of values of every group of strength sets.
+type coverage struct {
+	sets      []Set
+	rules     []Rule
+	sizes     []int
+	groups    []*tupleGroup
+	remaining int
+}
+
+func newCoverage(sets []Set, strength int) *coverage {
+	cov := &coverage{sets: sets, sizes: make([]int, len(sets))}
+	for k, set := range sets {
+		cov.sizes[k] = len(set.Values)
+	}
+	// enumerate all groups of strength sets, in lexicographic order
+	group := make([]int, strength)
+	for i := range group {
+		group[i] = i
+	}
+	for {
+		n := 1
+		for _, k := range group {
+			n *= cov.sizes[k]
+		}
+		cov.groups = append(cov.groups, &tupleGroup{
+			sets:    append([]int(nil), group...),
+			covered: make([]bool, n),
+		})
+		cov.remaining += n
+
+		i := strength - 1
+		for i > -1 && group[i] == len(sets)-strength+i {
+			i--
+		}
+		if i == -1 {
+			break
+		}
+		group[i]++
+		for j := i + 1; j < strength; j++ {
+			group[j] = group[j-1] + 1
+		}
+	}
+	return cov
+}
+
+// nextRow builds a row of value indices, one per set, starting from the first uncovered tuple,
+// and choosing for each remaining set the allowed value covering the most uncovered tuples.
+// It returns nil if all tuples are covered.
+func (cov *coverage) nextRow() []int {
+	for cov.remaining > 0 {
+		row := make([]int, len(cov.sizes))
+		for k := range row {
+			row[k] = -1
+		}
+		var seed *tupleGroup
+		for _, g := range cov.groups {
+			i := g.firstUncovered()
+			if i == -1 {
+				continue
+			}
+			for j := len(g.sets) - 1; j > -1; j-- {
+				k := g.sets[j]
+				row[k] = i % cov.sizes[k]
+				i /= cov.sizes[k]
+			}
+			seed = g
+			break
+		}
+		if cov.complete(row) {
+			return row
+		}
+		// no allowed row holds the seed tuple, leave it out
+		seed.covered[seed.tupleIndex(cov.sizes, row)] = true
+		cov.remaining--
+	}
+	return nil
+}
+
+// complete sets the values of row which aren't set yet.
+// It reports whether it could do so with all rules allowing row.
+//
+// Each value is chosen greedily to cover the most uncovered tuples. If rules reject all the values
+// of a set, the other values of the sets before it are tried, so that row is only left incomplete
+// if no allowed combination holds its values.
+func (cov *coverage) complete(row []int) bool {
+	if !cov.allows(row) {
+		return false
+	}
+	seed := append([]int(nil), row...)
+	for k := range row {
+		if seed[k] != -1 {
+			continue
+		}
+		best, bestGain := -1, -1
+		for v := 0; v < cov.sizes[k]; v++ {
+			row[k] = v
+			if !cov.allows(row) {
+				continue
+			}
+			if gain := cov.gain(row, k); gain > bestGain {
+				best, bestGain = v, gain
+			}
+		}
+		row[k] = best
+		if best == -1 {
+			copy(row, seed)
+			return cov.search(row, 0)
+		}
+	}
+	return true
+}
+
+// search sets the values of row which aren't set yet, from set k on, by trying all of them in order.
+// It reports whether it could do so with all rules allowing row, or else leaves row unchanged.
+func (cov *coverage) search(row []int, k int) bool {
+	for k < len(row) && row[k] != -1 {
+		k++
+	}
+	if k == len(row) {
+		return true
+	}
+	for v := 0; v < cov.sizes[k]; v++ {
+		row[k] = v
+		if cov.allows(row) && cov.search(row, k+1) {
+			return true
+		}
+	}
+	row[k] = -1
+	return false
+}
+
+// allows reports whether no rule rejects row, given the values set so far.
+func (cov *coverage) allows(row []int) bool {
+	return rowAllowed(cov.sets, cov.rules, row)
+}
+
+// rowAllowed reports whether no rule rejects row, a value index per set or -1 for the values not set yet.
+func rowAllowed(sets []Set, rules []Rule, row []int) bool {
+	return rowValue(sets, rules, row) != ruleFalse
+}
+
+// rowValue evaluates all rules for row, a value index per set or -1 for the values not set yet.
+// It returns ruleFalse if a rule rejects row, ruleTrue if all rules allow it whatever the values not set yet,
+// or else ruleUnknown.
+func rowValue(sets []Set, rules []Rule, row []int) ruleValue {
+	lookup := func(name string) (string, bool) {
+		for k, set := range sets {
+			if set.Name == name && row[k] != -1 {
+				return set.Values[row[k]], true
+			}
+		}
+		return "", false
+	}
+	v := ruleTrue
+	for _, r := range rules {
+		switch r.allows(lookup) {
+		case ruleFalse:
+			return ruleFalse
+		case ruleUnknown:
+			v = ruleUnknown
+		}
+	}
+	return v
+}
+
+// gain returns the number of uncovered tuples including set k which are fully determined by row.
+func (cov *coverage) gain(row []int, k int) int {
+	var n int
+	for _, g := range cov.groups {
+		if !g.has(k) {
+			continue
+		}
+		if i := g.tupleIndex(cov.sizes, row); i != -1 && !g.covered[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// cover marks all tuples of row as covered, but those with a value not set.
+func (cov *coverage) cover(row []int) {
+	for _, g := range cov.groups {
+		i := g.tupleIndex(cov.sizes, row)
+		if i != -1 && !g.covered[i] {
+			g.covered[i] = true
+			cov.remaining--
+		}
+	}
+}
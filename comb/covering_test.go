@@ -0,0 +1,82 @@
+package comb
+
+import "testing"
+
+// checkCovering fails tb if a tuple of strength values from sets isn't found in combinations.
+func checkCovering(tb testing.TB, sets []Set, strength int, combinations []Combination) {
+	cov := newCoverage(sets, strength)
+	for _, c := range combinations {
+		row := make([]int, len(sets))
+		for k, set := range sets {
+			row[k] = -1
+			for j, val := range set.Values {
+				if c[k].Name == set.Name && c[k].Value == val {
+					row[k] = j
+				}
+			}
+			if row[k] == -1 {
+				tb.Fatalf("%v is not made from the sets", c)
+			}
+		}
+		cov.cover(row)
+	}
+	if cov.remaining != 0 {
+		tb.Fatalf("%d tuples of strength %d are not covered", cov.remaining, strength)
+	}
+}
+
+func TestCoveringPairwise(t *testing.T) {
+	var sets []Set
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		sets = append(sets, Set{Name: name, Values: []string{"0", "1", "2", "3", "4"}})
+	}
+	combinations, err := NewCovering(sets, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCovering(t, sets, 2, combinations)
+	// 5^8 = 390625 combinations in the full product
+	if n := len(combinations); n > 50 {
+		t.Errorf("expected at most 50 combinations, got %d", n)
+	}
+}
+
+func TestCoveringStrength3(t *testing.T) {
+	sets := []Set{
+		{Name: "os", Values: []string{"linux", "windows", "darwin"}},
+		{Name: "arch", Values: []string{"amd64", "arm64", "386", "arm"}},
+		{Name: "cgo", Values: []string{"true", "false"}},
+		{Name: "race", Values: []string{"true", "false"}},
+		{Name: "tags", Values: []string{`""`, `"netgo"`, `"osusergo"`}},
+	}
+	combinations, err := NewCovering(sets, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCovering(t, sets, 3, combinations)
+}
+
+func TestCoveringFullStrength(t *testing.T) {
+	combinations, err := NewCovering(rankSets, len(rankSets))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(combinations); n != 12 {
+		t.Errorf("expected 12 combinations, got %d", n)
+	}
+}
+
+func TestCoveringErrors(t *testing.T) {
+	for _, strength := range []int{0, 4} {
+		if _, err := NewCovering(rankSets, strength); err != ErrInvalidStrength {
+			t.Errorf("strength %d: expected %v, got %v", strength, ErrInvalidStrength, err)
+		}
+	}
+	_, err := NewCovering([]Set{
+		{Name: "x", Values: []string{"0", "1"}},
+		{Name: "y", Values: []string{}},
+	}, 2)
+	if err != ErrSetNoValues {
+		t.Errorf("expected %v, got %v", ErrSetNoValues, err)
+	}
+}
@@ -0,0 +1,53 @@
+package comb
+
+import (
+	"encoding/csv"
+	"go/constant"
+	"go/token"
+	"io"
+)
+
+// WriteCSV writes the combinations yielded by it from sets to w as CSV records, using comma as the field delimiter,
+// e.g ',' for CSV or '\t' for TSV. The first record is a header with the set names, in order,
+// written even if there are no combinations.
+//
+// If unquote is true, values which are Go string literals are unquoted, e.g "Heart Red" is written Heart Red.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteCSV(w io.Writer, sets []Set, it Iterator, comma rune, unquote bool) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	header := make([]string, len(sets))
+	for i, set := range sets {
+		header[i] = set.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for {
+		c, ok := it.Next()
+		if !ok {
+			break
+		}
+		record := make([]string, len(c))
+		for i, e := range c {
+			record[i] = e.Value
+			if unquote {
+				record[i] = unquoteValue(e.Value)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// unquoteValue returns the string v holds if it's a Go string literal, or else v.
+func unquoteValue(v string) string {
+	if kind, val := parseLiteral(v); kind == token.STRING {
+		return constant.StringVal(val)
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package comb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart Red"`, `"Tile, Red"`}},
+		{Name: "n", Values: []string{"1", "f(1, 2)"}},
+	}
+	tests := []struct {
+		comma   rune
+		unquote bool
+		output  string
+	}{
+		{
+			comma: ',',
+			output: `card,n
+"""Heart Red""",1
+"""Heart Red""","f(1, 2)"
+"""Tile, Red""",1
+"""Tile, Red""","f(1, 2)"
+`,
+		},
+		{
+			comma:   ',',
+			unquote: true,
+			output: `card,n
+Heart Red,1
+Heart Red,"f(1, 2)"
+"Tile, Red",1
+"Tile, Red","f(1, 2)"
+`,
+		},
+		{
+			comma:   '\t',
+			unquote: true,
+			output: "card\tn\n" +
+				"Heart Red\t1\n" +
+				"Heart Red\tf(1, 2)\n" +
+				"Tile, Red\t1\n" +
+				"Tile, Red\tf(1, 2)\n",
+		},
+	}
+	for _, test := range tests {
+		it, err := NewIterator(sets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, sets, it, test.comma, test.unquote); err != nil {
+			t.Fatal(err)
+		}
+		if output := buf.String(); output != test.output {
+			t.Errorf("expected:\n%s\ngot:\n%s", test.output, output)
+		}
+	}
+}
+
+func TestWriteCSVNoCombinations(t *testing.T) {
+	sets := []Set{{Name: "a", Values: []string{"1"}}, {Name: "b", Values: []string{"2"}}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sets, SliceIterator(nil), ',', false); err != nil {
+		t.Fatal(err)
+	}
+	if output := buf.String(); output != "a,b\n" {
+		t.Errorf("expected a header, got %q", output)
+	}
+}
@@ -0,0 +1,146 @@
+package comb
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+)
+
+// ErrTableNotFound represents an error when a Go source has no test table with a given name.
+var ErrTableNotFound = errors.New("test table not found")
+
+// ReadTable reads the rows of the test table named name in the Go source src,
+// declared with var or := as a composite literal of keyed struct literals, e.g:
+//
+//	tests := []struct {
+//		card   string
+//		figure string
+//	}{
+//		{card: "Heart", figure: "Jack"},
+//		{card: "Tile", figure: "Queen"},
+//	}
+//
+// The values of the rows are their source text, e.g "Heart" with its quotes.
+// If the struct type of the rows is declared in the literal, the types of its fields are returned by name.
+// filename is only used in errors.
+//
+// It returns an error, ErrTableNotFound if there is no such table, or ErrInvalidCombination if a row isn't keyed.
+func ReadTable(filename string, src []byte, name string) ([]Combination, map[string]string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	table := findTable(f, name)
+	if table == nil {
+		return nil, nil, fmt.Errorf("%s: %s: %w", filename, name, ErrTableNotFound)
+	}
+
+	types := make(map[string]string)
+	if at, ok := table.Type.(*ast.ArrayType); ok {
+		if st, ok := at.Elt.(*ast.StructType); ok {
+			for _, field := range st.Fields.List {
+				start := fset.Position(field.Type.Pos()).Offset
+				end := fset.Position(field.Type.End()).Offset
+				for _, name := range field.Names {
+					types[name.Name] = string(src[start:end])
+				}
+			}
+		}
+	}
+
+	var combinations []Combination
+	for _, elt := range table.Elts {
+		if u, ok := elt.(*ast.UnaryExpr); ok && u.Op == token.AND {
+			elt = u.X
+		}
+		lit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: %w", fset.Position(elt.Pos()), ErrInvalidCombination)
+		}
+		c, err := combinationFromLit(fset, src, lit)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", fset.Position(elt.Pos()), err)
+		}
+		combinations = append(combinations, c)
+	}
+	return combinations, types, nil
+}
+
+// findTable returns the composite literal assigned to name in f, or nil if there is none.
+func findTable(f *ast.File, name string) *ast.CompositeLit {
+	var table *ast.CompositeLit
+	ast.Inspect(f, func(n ast.Node) bool {
+		if table != nil {
+			return false
+		}
+		var (
+			lhs []*ast.Ident
+			rhs []ast.Expr
+		)
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			lhs, rhs = n.Names, n.Values
+		case *ast.AssignStmt:
+			for _, expr := range n.Lhs {
+				ident, _ := expr.(*ast.Ident)
+				lhs = append(lhs, ident)
+			}
+			rhs = n.Rhs
+		default:
+			return true
+		}
+		for i, ident := range lhs {
+			if ident == nil || ident.Name != name || i >= len(rhs) {
+				continue
+			}
+			if lit, ok := rhs[i].(*ast.CompositeLit); ok {
+				table = lit
+			}
+		}
+		return true
+	})
+	return table
+}
+
+// ExtractSets returns the sets made of the distinct values of each field of combinations,
+// in the order they first appear. The types of the sets are taken from types, by name.
+func ExtractSets(combinations []Combination, types map[string]string) []Set {
+	var sets []Set
+	index := make(map[string]int)
+	seen := make(map[Element]bool)
+	for _, c := range combinations {
+		for _, e := range c {
+			k, ok := index[e.Name]
+			if !ok {
+				k = len(sets)
+				index[e.Name] = k
+				sets = append(sets, Set{Name: e.Name, Type: types[e.Name], Values: []string{}})
+			}
+			if !seen[e] {
+				seen[e] = true
+				sets[k].Values = append(sets[k].Values, e.Value)
+			}
+		}
+	}
+	return sets
+}
+
+// WriteSets writes sets to w in the syntax read by ParseSets, one per line.
+//
+// It returns an error if a set can't be marshaled or if an error occurs when writing to w.
+func WriteSets(w io.Writer, sets []Set) error {
+	for _, set := range sets {
+		b, err := set.MarshalText()
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package comb
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const extractSrc = `package cards
+
+import "testing"
+
+var other = []struct{ card string }{{card: "Pike"}}
+
+func TestCards(t *testing.T) {
+	tests := []struct {
+		card, figure string
+		n            int
+	}{
+		{card: "Heart", figure: "Jack", n: 1},
+		{card: "Heart", figure: "Queen", n: 0x2},
+		{figure: "Jack", card: "Tile", n: f(1, 2)},
+	}
+	_ = tests
+}
+`
+
+func TestReadTable(t *testing.T) {
+	combinations, types, err := ReadTable("cards_test.go", []byte(extractSrc), "tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(combinations); n != 3 {
+		t.Fatalf("expected 3 rows, got %d", n)
+	}
+	expected := Combination{{Name: "figure", Value: `"Jack"`}, {Name: "card", Value: `"Tile"`}, {Name: "n", Value: "f(1, 2)"}}
+	if !reflect.DeepEqual(combinations[2], expected) {
+		t.Errorf("expected %#v, got %#v", expected, combinations[2])
+	}
+	expectedTypes := map[string]string{"card": "string", "figure": "string", "n": "int"}
+	if !reflect.DeepEqual(types, expectedTypes) {
+		t.Errorf("expected %#v, got %#v", expectedTypes, types)
+	}
+
+	sets := ExtractSets(combinations, types)
+	expectedSets := []Set{
+		{Name: "card", Type: "string", Values: []string{`"Heart"`, `"Tile"`}},
+		{Name: "figure", Type: "string", Values: []string{`"Jack"`, `"Queen"`}},
+		{Name: "n", Type: "int", Values: []string{"1", "0x2", "f(1, 2)"}},
+	}
+	if !reflect.DeepEqual(sets, expectedSets) {
+		t.Fatalf("expected:\n%#v\ngot:\n%#v", expectedSets, sets)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSets(&buf, sets); err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := ParseSets(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, sets) {
+		t.Errorf("expected the written sets to be read back, got:\n%#v", parsed)
+	}
+}
+
+func TestReadTableVar(t *testing.T) {
+	combinations, _, err := ReadTable("cards_test.go", []byte(extractSrc), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Combination{{{Name: "card", Value: `"Pike"`}}}
+	if !reflect.DeepEqual(combinations, expected) {
+		t.Errorf("expected %#v, got %#v", expected, combinations)
+	}
+}
+
+func TestReadTableErrors(t *testing.T) {
+	if _, _, err := ReadTable("cards_test.go", []byte(extractSrc), "nope"); !errors.Is(err, ErrTableNotFound) {
+		t.Errorf("expected %v, got %v", ErrTableNotFound, err)
+	}
+	src := strings.Replace(extractSrc, `{card: "Heart", figure: "Jack", n: 1}`, `{"Heart", "Jack", 1}`, 1)
+	if _, _, err := ReadTable("cards_test.go", []byte(src), "tests"); !errors.Is(err, ErrInvalidCombination) {
+		t.Errorf("expected %v, got %v", ErrInvalidCombination, err)
+	}
+}
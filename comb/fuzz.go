@@ -0,0 +1,118 @@
+package comb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ErrFuzzType represents an error when the type of a set isn't one of the types of fuzz arguments supported.
+var ErrFuzzType = errors.New("unsupported fuzz argument type")
+
+// ErrFuzzValue represents an error when a value isn't a literal of the type of its fuzz argument.
+var ErrFuzzValue = errors.New("value is not a literal of its fuzz argument type")
+
+// WriteFuzzCorpus writes a file in dir for each combination yielded by it,
+// to seed a fuzz target, e.g with dir testdata/fuzz/FuzzParse for the target FuzzParse.
+// Files are in the go test fuzz v1 encoding, and named after the SHA-256 of their content as go test does.
+// dir is created if needed.
+//
+// Each set is an argument of the fuzz target, in order, of the type returned by Set.GoType:
+// string, []byte, int, float64 or bool. Values must be Go literals, which are converted to that type,
+// e.g 1 is float64(1) for a float64 set and "abc" is []byte("abc") for a []byte one.
+//
+// It returns an error, ErrFuzzType if the type of a set isn't supported, ErrFuzzValue if a value
+// isn't a literal of its type, ErrNotInSets if a combination isn't made from sets or an error if a file can't be written.
+func WriteFuzzCorpus(dir string, sets []Set, it Iterator) error {
+	types := make([]string, len(sets))
+	for k, set := range sets {
+		types[k] = set.GoType()
+		switch types[k] {
+		case "string", "[]byte", "int", "float64", "bool":
+		default:
+			return fmt.Errorf("set %s: %s: %w", set.Name, types[k], ErrFuzzType)
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for {
+		c, ok := it.Next()
+		if !ok {
+			return nil
+		}
+		data, err := fuzzEntry(sets, types, c)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+}
+
+// fuzzEntry returns the go test fuzz v1 encoding of c, with the value of sets[k] of type types[k].
+func fuzzEntry(sets []Set, types []string, c Combination) ([]byte, error) {
+	if len(c) != len(sets) {
+		return nil, ErrNotInSets
+	}
+	var buf bytes.Buffer
+	buf.WriteString("go test fuzz v1\n")
+	for k, e := range c {
+		if e.Name != sets[k].Name {
+			return nil, ErrNotInSets
+		}
+		arg, ok := fuzzArg(types[k], e.Value)
+		if !ok {
+			return nil, fmt.Errorf("set %s: %s: %w", e.Name, e.Value, ErrFuzzValue)
+		}
+		buf.WriteString(arg)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// fuzzArg returns the go test fuzz v1 encoding of the literal v converted to typ.
+// It reports false if v isn't a literal which can be converted to typ.
+func fuzzArg(typ, v string) (string, bool) {
+	kind, val := parseLiteral(v)
+	switch typ {
+	case "string", "[]byte":
+		if kind != token.STRING {
+			return "", false
+		}
+		return typ + "(" + strconv.Quote(constant.StringVal(val)) + ")", true
+	case "int":
+		if kind != token.INT && kind != token.FLOAT && kind != token.CHAR {
+			return "", false
+		}
+		i, exact := constant.Int64Val(constant.ToInt(val))
+		if !exact || int64(int(i)) != i {
+			return "", false
+		}
+		return fmt.Sprintf("int(%d)", i), true
+	case "float64":
+		if kind != token.INT && kind != token.FLOAT {
+			return "", false
+		}
+		f, _ := constant.Float64Val(val)
+		if math.IsInf(f, 0) {
+			return "", false
+		}
+		return fmt.Sprintf("float64(%v)", f), true
+	case "bool":
+		if kind != token.IDENT {
+			return "", false
+		}
+		return fmt.Sprintf("bool(%v)", constant.BoolVal(val)), true
+	}
+	return "", false
+}
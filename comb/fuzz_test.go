@@ -0,0 +1,72 @@
+package comb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFuzzCorpus(t *testing.T) {
+	sets := []Set{
+		{Name: "s", Values: []string{`"a b"`, "`raw`"}},
+		{Name: "b", Type: "[]byte", Values: []string{`"\x00"`}},
+		{Name: "n", Type: "int", Values: []string{"-1", "0x10", "'a'"}},
+		{Name: "x", Type: "float64", Values: []string{"1", "2.5"}},
+		{Name: "ok", Values: []string{"true"}},
+	}
+	it, err := NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "testdata", "fuzz", "FuzzParse")
+	if err := WriteFuzzCorpus(dir, sets, it); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(entries); n != 12 {
+		t.Fatalf("expected 12 files, got %d", n)
+	}
+
+	expected := `go test fuzz v1
+string("raw")
+[]byte("\x00")
+int(97)
+float64(2.5)
+bool(true)
+`
+	name := "7d7a0854c0d27d04"
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, b)
+	}
+}
+
+func TestWriteFuzzCorpusErrors(t *testing.T) {
+	tests := []struct {
+		sets []Set
+		err  error
+	}{
+		{sets: []Set{{Name: "r", Values: []string{"'a'"}}}, err: ErrFuzzType},
+		{sets: []Set{{Name: "v", Values: []string{"x", "1"}}}, err: ErrFuzzType},
+		{sets: []Set{{Name: "n", Type: "int", Values: []string{"1.5"}}}, err: ErrFuzzValue},
+		{sets: []Set{{Name: "s", Type: "string", Values: []string{"1"}}}, err: ErrFuzzValue},
+		{sets: []Set{{Name: "x", Type: "float64", Values: []string{"1e400"}}}, err: ErrFuzzValue},
+	}
+	for _, test := range tests {
+		it, err := NewIterator(test.sets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = WriteFuzzCorpus(t.TempDir(), test.sets, it)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%v: expected %v, got %v", test.sets, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package comb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// ErrPackageAmbiguous represents an error when a package name is the one of several packages of the standard library.
+var ErrPackageAmbiguous = errors.New("package name matches several packages of the standard library")
+
+// GoType returns the Go type of the values of s: its Type if set,
+// or else the type of its values if they are all literals of the same kind.
+// Integer and floating-point values together are float64.
+// Values of different kinds, or which aren't literals, are interface{}.
+func (s Set) GoType() string {
+	if s.Type != "" {
+		return s.Type
+	}
+	var typ string
+	for _, val := range s.Values {
+		t := literalType(val)
+		switch {
+		case t == "":
+			return "interface{}"
+		case typ == "" || typ == t:
+			typ = t
+		case (typ == "int" && t == "float64") || (typ == "float64" && t == "int"):
+			typ = "float64"
+		default:
+			return "interface{}"
+		}
+	}
+	if typ == "" {
+		return "interface{}"
+	}
+	return typ
+}
+
+// WriteTable writes to w the declaration of a test table named name, holding the combinations yielded by it:
+//
+//	tests := []struct {
+//		card   string
+//		figure string
+//	}{
+//		{card: "Heart", figure: "Jack"},
+//		...
+//	}
+//
+// The struct has one field per set, with the type returned by Set.GoType.
+// The declaration is formatted with go/format.
+//
+// It returns an error if the declaration isn't valid Go or if an error occurs when writing to w.
+func WriteTable(w io.Writer, name string, sets []Set, it Iterator) error {
+	var buf bytes.Buffer
+	if err := writeTable(&buf, name, sets, it); err != nil {
+		return err
+	}
+	return writeFormatted(w, buf.Bytes())
+}
+
+// writeTable writes the unformatted declaration of a test table to buf.
+func writeTable(buf *bytes.Buffer, name string, sets []Set, it Iterator) error {
+	fmt.Fprintf(buf, "%s := []struct {\n", name)
+	for _, set := range sets {
+		fmt.Fprintf(buf, "%s %s\n", set.Name, set.GoType())
+	}
+	fmt.Fprintln(buf, "}{")
+	if err := WriteIterator(buf, it); err != nil {
+		return err
+	}
+	fmt.Fprintln(buf, "}")
+	return nil
+}
+
+// WriteTestFile writes to w a Go test file of package pkg, with a test function named funcName
+// which runs a subtest for each row of the test table declared like with WriteTable.
+// Subtests are named after the values of their row, e.g card=Heart/figure=Jack.
+//
+// The test function is a skeleton to complete: each subtest is skipped.
+// The file imports fmt, testing and the packages of the standard library the values refer to,
+// e.g net/http for http.StatusOK. Other names, such as cfg in cfg.Name, are taken to be declared in pkg.
+//
+// It returns an error, ErrPackageAmbiguous if a value refers to a package name
+// matching several packages of the standard library, e.g rand, an error if the file isn't valid Go
+// or if an error occurs when writing to w.
+func WriteTestFile(w io.Writer, pkg string, funcName string, name string, sets []Set, it Iterator) error {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "func %s(t *testing.T) {\n", funcName)
+	if err := writeTable(&body, name, sets, it); err != nil {
+		return err
+	}
+	nameFormat, args := "", ""
+	for i, set := range sets {
+		if i > 0 {
+			nameFormat += "/"
+		}
+		nameFormat += set.Name + "=%v"
+		args += ", tt." + set.Name
+	}
+	fmt.Fprintf(&body, "for _, tt := range %s {\n", name)
+	fmt.Fprintf(&body, "t.Run(fmt.Sprintf(%q%s), func(t *testing.T) {\n", nameFormat, args)
+	body.WriteString("t.Skipf(\"TODO: test %+v\", tt)\n")
+	body.WriteString("})\n}\n}\n")
+
+	imports, err := testFileImports(body.Bytes())
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n", pkg)
+	for _, path := range imports {
+		fmt.Fprintf(&buf, "%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(body.Bytes())
+	return writeFormatted(w, buf.Bytes())
+}
+
+// testFileImports returns the sorted import paths of the packages the declarations in src refer to:
+// fmt, testing and the packages of the standard library named by the selectors whose name isn't declared in src.
+func testFileImports(src []byte) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), src...), 0)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			// the parser leaves the names it can't resolve in the file without an object
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				names[id.Name] = true
+			}
+		}
+		return true
+	})
+	delete(names, "fmt")
+	delete(names, "testing")
+	paths := []string{"fmt", "testing"}
+	for name := range names {
+		path, err := stdPackagePath(name)
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// stdPackagePath returns the import path of the package of the standard library named name, or "" if there is none.
+//
+// It returns an error, ErrPackageAmbiguous if there are several or an error if the standard library can't be read.
+func stdPackagePath(name string) (string, error) {
+	root := filepath.Join(build.Default.GOROOT, "src")
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		switch d.Name() {
+		case "cmd", "internal", "testdata", "vendor":
+			return filepath.SkipDir
+		case name:
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return "", err
+	case len(paths) > 1:
+		return "", fmt.Errorf("%w: %s could be %v", ErrPackageAmbiguous, name, paths)
+	case len(paths) == 1:
+		return paths[0], nil
+	}
+	return "", nil
+}
+
+// writeFormatted formats the Go source src with go/format and writes it to w.
+func writeFormatted(w io.Writer, src []byte) error {
+	b, err := format.Source(src)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
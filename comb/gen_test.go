@@ -0,0 +1,149 @@
+package comb
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGoType(t *testing.T) {
+	tests := []struct {
+		set Set
+		typ string
+	}{
+		{set: Set{Name: "s", Values: []string{`"X"`, "`Y`"}}, typ: "string"},
+		{set: Set{Name: "i", Values: []string{"0xEDEA", "42", "-1"}}, typ: "int"},
+		{set: Set{Name: "f", Values: []string{"42", "0.5", "-1e3"}}, typ: "float64"},
+		{set: Set{Name: "b", Values: []string{"true", "false"}}, typ: "bool"},
+		{set: Set{Name: "r", Values: []string{"'a'", `'\n'`}}, typ: "rune"},
+		{set: Set{Name: "m", Values: []string{`"X"`, "42"}}, typ: "interface{}"},
+		{set: Set{Name: "e", Values: []string{"rga()", `""`}}, typ: "interface{}"},
+		{set: Set{Name: "n", Values: []string{"-x", "1"}}, typ: "interface{}"},
+		{set: Set{Name: "h", Type: "int", Values: []string{"http.StatusOK"}}, typ: "int"},
+	}
+	for _, test := range tests {
+		if typ := test.set.GoType(); typ != test.typ {
+			t.Errorf("%v: expected %s, got %s", test.set.Values, test.typ, typ)
+		}
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart"`, `"Tile"`}},
+		{Name: "n", Values: []string{"1", "2"}},
+		{Name: "status", Type: "int", Values: []string{"http.StatusOK"}},
+	}
+	it, err := NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, "tests", sets, it); err != nil {
+		t.Fatal(err)
+	}
+	expected := `tests := []struct {
+	card   string
+	n      int
+	status int
+}{
+	{card: "Heart", n: 1, status: http.StatusOK},
+	{card: "Heart", n: 2, status: http.StatusOK},
+	{card: "Tile", n: 1, status: http.StatusOK},
+	{card: "Tile", n: 2, status: http.StatusOK},
+}
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestWriteTableInvalid(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart`}},
+	}
+	it, err := NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, "tests", sets, it); err == nil {
+		t.Error("expected a non-nil error, got nil")
+	}
+}
+
+func TestWriteTestFile(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart"`, `"Tile"`}},
+		{Name: "figure", Values: []string{`"Jack"`}},
+	}
+	it, err := NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTestFile(&buf, "cards", "TestCardFigure", "tests", sets, it); err != nil {
+		t.Fatal(err)
+	}
+	expected := `package cards
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCardFigure(t *testing.T) {
+	tests := []struct {
+		card   string
+		figure string
+	}{
+		{card: "Heart", figure: "Jack"},
+		{card: "Tile", figure: "Jack"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("card=%v/figure=%v", tt.card, tt.figure), func(t *testing.T) {
+			t.Skipf("TODO: test %+v", tt)
+		})
+	}
+}
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestWriteTestFileImports(t *testing.T) {
+	sets := []Set{
+		{Name: "status", Type: "int", Values: []string{"http.StatusOK"}},
+		{Name: "d", Type: "time.Duration", Values: []string{"time.Second"}},
+		{Name: "name", Type: "string", Values: []string{"cfg.Name"}},
+	}
+	it, err := NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTestFile(&buf, "server", "TestStatus", "tests", sets, it); err != nil {
+		t.Fatal(err)
+	}
+	expected := `import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+`
+	if output := buf.String(); !strings.Contains(output, expected) {
+		t.Errorf("expected the imports:\n%s\ngot:\n%s", expected, output)
+	}
+
+	sets = []Set{{Name: "n", Values: []string{"rand.Int()"}}}
+	it, err = NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteTestFile(&buf, "server", "TestN", "tests", sets, it); !errors.Is(err, ErrPackageAmbiguous) {
+		t.Errorf("expected %v, got %v", ErrPackageAmbiguous, err)
+	}
+}
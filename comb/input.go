@@ -0,0 +1,391 @@
+package comb
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrInputValue represents an error when a value of sets read from JSON, YAML or TOML isn't a scalar.
+var ErrInputValue = errors.New("set value is not a scalar")
+
+// ErrInputSyntax represents an error when sets read from YAML or TOML use a syntax which isn't supported.
+var ErrInputSyntax = errors.New("unsupported syntax")
+
+// InputFormat returns the format of the sets file named name, from its extension:
+// json, yaml or toml, or else sets for the line format.
+func InputFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	}
+	return "sets"
+}
+
+// ParseSetsFormat parses sets in format, which is sets (see ParseSets), json, yaml or toml.
+//
+// In JSON, YAML and TOML, each top-level key is the name of a set, optionally followed by its type,
+// and its array holds the values. Only a simple subset of YAML and TOML is supported.
+// Strings are taken as is, like the unquoted values of the line format, and other scalars are turned
+// into the same Go literal, e.g 42, 0.5, true, or nil for null.
+// Values of sets with a type are checked against it as in the line format, strings being unquoted values.
+// Rules are only supported in the line format.
+func ParseSetsFormat(format string, r io.Reader) ([]Set, []Rule, error) {
+	var (
+		sets []Set
+		err  error
+	)
+	switch format {
+	case "sets":
+		return ParseSets(r)
+	case "json":
+		sets, err = parseSetsJSON(r)
+	case "yaml":
+		sets, err = parseSetsYAML(r)
+	case "toml":
+		sets, err = parseSetsTOML(r)
+	default:
+		return nil, nil, fmt.Errorf("unknown input format %q", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, set := range sets {
+		for i, v := range set.Values {
+			if set.Values[i], err = typedValue(set.Type, v, false); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", set.Name, err)
+			}
+		}
+	}
+	return sets, nil, nil
+}
+
+// newInputSet returns an empty set for key, which holds its name and optional type.
+func newInputSet(key string) (Set, error) {
+	var set Set
+	set.Name, set.Type = splitSetName(key)
+	if set.Name == "" {
+		return set, ErrSetInvalidName
+	}
+	set.Values = []string{}
+	return set, nil
+}
+
+func parseSetsJSON(r io.Reader) ([]Set, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("sets are not a JSON object")
+	}
+	var sets []Set
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		set, err := newInputSet(tok.(string))
+		if err != nil {
+			return nil, err
+		}
+		var raw interface{}
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		values, ok := raw.([]interface{})
+		if !ok {
+			values = []interface{}{raw}
+		}
+		for _, v := range values {
+			var val string
+			switch v := v.(type) {
+			case string:
+				val = v
+			case json.Number:
+				val = v.String()
+			case bool:
+				val = strconv.FormatBool(v)
+			case nil:
+				val = "nil"
+			default:
+				return nil, fmt.Errorf("%s: %w", set.Name, ErrInputValue)
+			}
+			set.Values = append(set.Values, val)
+		}
+		sets = append(sets, set)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// parseSetsYAML parses a YAML mapping of sequences, in flow style:
+//
+//	card: [Heart, Tile, "Clover Black"]
+//
+// or block style, with items indented or not:
+//
+//	card:
+//	  - Heart
+//	  - Tile
+//	figure:
+//	- Jack
+func parseSetsYAML(r io.Reader) ([]Set, error) {
+	var (
+		sets []Set
+		// block reports whether the last key has an empty value, followed by the items of a block sequence.
+		block bool
+	)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(stripInputComment(scanner.Text()), " \t")
+		text := strings.TrimLeft(line, " \t")
+		switch {
+		case text == "" || text == "---":
+			continue
+		case strings.HasPrefix(text, "- "), text == "-":
+			if len(sets) == 0 || len(line) == len(text) && !block {
+				return nil, &ParseError{Line: n, Column: 1, Err: ErrInputSyntax}
+			}
+			val, err := yamlScalar(strings.TrimSpace(text[1:]))
+			if err != nil {
+				return nil, &ParseError{Line: n, Column: len(line) - len(text) + 1, Err: err}
+			}
+			sets[len(sets)-1].Values = append(sets[len(sets)-1].Values, val)
+			continue
+		case len(line) != len(text):
+			return nil, &ParseError{Line: n, Column: 1, Err: ErrInputSyntax}
+		}
+		i := strings.Index(text, ":")
+		if i == -1 {
+			return nil, &ParseError{Line: n, Column: len(text) + 1, Err: ErrSetNoColon}
+		}
+		key, err := yamlScalar(strings.TrimSpace(text[:i]))
+		if err != nil {
+			return nil, &ParseError{Line: n, Column: 1, Err: err}
+		}
+		set, err := newInputSet(key)
+		if err != nil {
+			return nil, &ParseError{Line: n, Column: 1, Err: err}
+		}
+		value := strings.TrimSpace(text[i+1:])
+		block = value == ""
+		var items []string
+		switch {
+		case value == "":
+		case strings.HasPrefix(value, "["):
+			if items, err = splitFlow(value); err != nil {
+				return nil, &ParseError{Line: n, Column: i + 2, Err: err}
+			}
+		default:
+			items = []string{value}
+		}
+		for _, item := range items {
+			val, err := yamlScalar(item)
+			if err != nil {
+				return nil, &ParseError{Line: n, Column: i + 2, Err: err}
+			}
+			set.Values = append(set.Values, val)
+		}
+		sets = append(sets, set)
+	}
+	return sets, scanner.Err()
+}
+
+// yamlScalar returns the value of a YAML scalar.
+func yamlScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		return strconv.Unquote(s)
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", ErrValueNoClosingQuote
+		}
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1), nil
+	case strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{"):
+		return "", ErrInputValue
+	case s == "null" || s == "~":
+		return "nil", nil
+	}
+	return s, nil
+}
+
+// parseSetsTOML parses TOML key/value pairs of arrays:
+//
+//	card = ["Heart", "Tile", 'Clover Black']
+//	n = [
+//	  1, 2,
+//	  3,
+//	]
+func parseSetsTOML(r io.Reader) ([]Set, error) {
+	var (
+		sets  []Set
+		text  string
+		start int
+	)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(stripInputComment(scanner.Text()))
+		if text == "" {
+			if line == "" {
+				continue
+			}
+			if strings.HasPrefix(line, "[") {
+				return nil, &ParseError{Line: n, Column: 1, Err: ErrInputSyntax}
+			}
+			start = n
+		}
+		text += line + " "
+		i := strings.Index(text, "=")
+		if i == -1 {
+			return nil, &ParseError{Line: start, Column: 1, Err: ErrInputSyntax}
+		}
+		value := strings.TrimSpace(text[i+1:])
+		if strings.HasPrefix(value, "[") && !flowClosed(value) {
+			continue
+		}
+		key, err := tomlScalar(strings.TrimSpace(text[:i]))
+		if err != nil {
+			return nil, &ParseError{Line: start, Column: 1, Err: err}
+		}
+		set, err := newInputSet(key)
+		if err != nil {
+			return nil, &ParseError{Line: start, Column: 1, Err: err}
+		}
+		items := []string{value}
+		if strings.HasPrefix(value, "[") {
+			if items, err = splitFlow(value); err != nil {
+				return nil, &ParseError{Line: start, Column: i + 2, Err: err}
+			}
+		}
+		for _, item := range items {
+			val, err := tomlScalar(item)
+			if err != nil {
+				return nil, &ParseError{Line: start, Column: i + 2, Err: err}
+			}
+			set.Values = append(set.Values, val)
+		}
+		sets = append(sets, set)
+		text = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if text != "" {
+		return nil, &ParseError{Line: start, Column: 1, Err: ErrInputSyntax}
+	}
+	return sets, nil
+}
+
+// tomlScalar returns the value of a TOML scalar, or of a bare key.
+func tomlScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		return strconv.Unquote(s)
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", ErrValueNoClosingQuote
+		}
+		return s[1 : len(s)-1], nil
+	case strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{"):
+		return "", ErrInputValue
+	}
+	return s, nil
+}
+
+// splitFlow splits a flow sequence or array, e.g [a, "b, c", 'd'], in its items.
+func splitFlow(s string) ([]string, error) {
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") || !flowClosed(s) {
+		return nil, ErrInputSyntax
+	}
+	var (
+		items []string
+		quote byte
+		from  = 1
+	)
+	for i := 1; i < len(s)-1; i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			return nil, ErrInputValue
+		case c == ',':
+			items = append(items, strings.TrimSpace(s[from:i]))
+			from = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[from : len(s)-1]); last != "" {
+		items = append(items, last)
+	}
+	for _, item := range items {
+		if item == "" {
+			return nil, ErrInputSyntax
+		}
+	}
+	return items, nil
+}
+
+// flowClosed reports whether the brackets of s are balanced, outside of quotes.
+func flowClosed(s string) bool {
+	var (
+		depth int
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return depth == 0 && quote == 0
+}
+
+// stripInputComment returns line without its # comment, if any, outside of quotes.
+func stripInputComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,125 @@
+package comb
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var inputSets = []Set{
+	{Name: "card", Values: []string{`"Heart Red"`, "Tile", "Clover, Black"}},
+	{Name: "n", Values: []string{"1", "2.5", "0x10"}},
+	{Name: "ok", Values: []string{"true", "nil"}},
+	{Name: "status", Type: "int", Values: []string{"http.StatusOK"}},
+	{Name: "empty", Values: []string{}},
+}
+
+func TestParseSetsFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		input  string
+	}{
+		{
+			format: "json",
+			input: `{
+	"card": ["\"Heart Red\"", "Tile", "Clover, Black"],
+	"n": [1, 2.5, "0x10"],
+	"ok": [true, null],
+	"status int": "http.StatusOK",
+	"empty": []
+}`,
+		},
+		{
+			format: "yaml",
+			input: `# cards
+card: ['"Heart Red"', Tile, "Clover, Black"]
+n:
+  - 1
+  - 2.5 # not 2
+  - 0x10
+ok: [true, ~]
+"status int": http.StatusOK
+empty: []
+`,
+		},
+		{
+			format: "yaml",
+			input: `card:
+- '"Heart Red"'
+- Tile
+- "Clover, Black"
+n: [1, 2.5, 0x10]
+ok:
+- true
+-   ~
+"status int": http.StatusOK
+empty: []
+`,
+		},
+		{
+			format: "toml",
+			input: `# cards
+card = ['"Heart Red"', "Tile", "Clover, Black"]
+n = [
+  1, 2.5, # not 2
+  0x10,
+]
+ok = [true, "nil"]
+"status int" = "http.StatusOK"
+empty = []
+`,
+		},
+	}
+	for _, test := range tests {
+		sets, rules, err := ParseSetsFormat(test.format, strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("%s: %v", test.format, err)
+			continue
+		}
+		if rules != nil {
+			t.Errorf("%s: expected no rules, got %#v", test.format, rules)
+		}
+		if !reflect.DeepEqual(sets, inputSets) {
+			t.Errorf("%s: expected:\n%#v\ngot:\n%#v", test.format, inputSets, sets)
+		}
+	}
+}
+
+func TestParseSetsFormatErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		input  string
+		err    error
+	}{
+		{format: "json", input: `{"card": [["Heart"]]}`, err: ErrInputValue},
+		{format: "yaml", input: "card: [Heart, [Tile]]", err: ErrInputValue},
+		{format: "yaml", input: "card:\n  figure: Jack", err: ErrInputSyntax},
+		{format: "yaml", input: "card: [Heart]\n- Tile", err: ErrInputSyntax},
+		{format: "toml", input: "[cards]\ncard = [1]", err: ErrInputSyntax},
+		{format: "toml", input: "card = [1,\n2", err: ErrInputSyntax},
+		{format: "json", input: `{"port int": [80, "443", true]}`, err: ErrValueType},
+	}
+	for _, test := range tests {
+		_, _, err := ParseSetsFormat(test.format, strings.NewReader(test.input))
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s %q: expected %v, got %v", test.format, test.input, test.err, err)
+		}
+	}
+}
+
+func TestInputFormat(t *testing.T) {
+	tests := map[string]string{
+		"cards.sets": "sets",
+		"-":          "sets",
+		"cards.json": "json",
+		"cards.YML":  "yaml",
+		"cards.yaml": "yaml",
+		"cards.toml": "toml",
+	}
+	for name, format := range tests {
+		if f := InputFormat(name); f != format {
+			t.Errorf("%s: expected %s, got %s", name, format, f)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package comb
+
+// Iterator is the interface implemented by types which yield combinations one at a time.
+type Iterator interface {
+	// Next returns the next combination and true,
+	// or nil and false when there are no more combinations.
+	Next() (Combination, bool)
+}
+
+// IndexedIterator is an Iterator which knows the index, in the order of New, of the combinations it yields.
+type IndexedIterator interface {
+	Iterator
+	// Index returns the index of the combination last returned by Next.
+	Index() int
+}
+
+// ProductIterator yields all the combinations from sets allowed by rules, in the same order as New.
+//
+// Only the current position is held in memory, so it can go through more combinations than would fit at once.
+type ProductIterator struct {
+	sets    []Set
+	rules   []Rule
+	indices []int
+	len     int
+	index   int
+	next    int
+	done    bool
+}
+
+// NewIterator creates an iterator over all the combinations from sets allowed by rules.
+//
+// It returns the iterator or an error, ErrSetNoValues if one of the sets provided has no values,
+// an *OverflowError if there are too many combinations to index them or a *RuleError if a rule refers to an unknown set.
+func NewIterator(sets []Set, rules ...Rule) (*ProductIterator, error) {
+	n, err := numCombinations(sets)
+	if err != nil {
+		return nil, err
+	}
+	rules, err = checkRules(sets, rules)
+	if err != nil {
+		return nil, err
+	}
+	return &ProductIterator{
+		sets:    sets,
+		rules:   rules,
+		indices: make([]int, len(sets)),
+		len:     n,
+		index:   -1,
+		done:    n == 0,
+	}, nil
+}
+
+// Len returns the total number of combinations of the iterator, before rules are applied.
+func (it *ProductIterator) Len() int {
+	return it.len
+}
+
+// Next implements Iterator.
+func (it *ProductIterator) Next() (Combination, bool) {
+	for !it.done {
+		it.index = it.next
+		c := it.advance()
+		if allowed(it.rules, c) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Index implements IndexedIterator.
+func (it *ProductIterator) Index() int {
+	return it.index
+}
+
+func (it *ProductIterator) advance() Combination {
+	c := make(Combination, len(it.sets))
+	for i, set := range it.sets {
+		c[i] = Element{Name: set.Name, Value: set.Values[it.indices[i]]}
+	}
+	it.next++
+	// the last set moves first, like the digits of a number
+	for i := len(it.indices) - 1; ; i-- {
+		if i < 0 {
+			it.done = true
+			break
+		}
+		it.indices[i]++
+		if it.indices[i] < len(it.sets[i].Values) {
+			break
+		}
+		it.indices[i] = 0
+	}
+	return c
+}
+
+type sliceIterator []Combination
+
+// SliceIterator returns an iterator over combinations.
+func SliceIterator(combinations []Combination) Iterator {
+	s := sliceIterator(combinations)
+	return &s
+}
+
+// Next implements Iterator.
+func (s *sliceIterator) Next() (Combination, bool) {
+	if len(*s) == 0 {
+		return nil, false
+	}
+	c := (*s)[0]
+	*s = (*s)[1:]
+	return c, true
+}
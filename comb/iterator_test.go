@@ -0,0 +1,83 @@
+package comb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestIteratorSameOrderAsNew(t *testing.T) {
+	sets := []Set{
+		{Name: "S1", Values: []string{`"X"`, `"Y"`}},
+		{Name: "S2", Values: []string{`"µ"`}},
+		{Name: "I3", Values: []string{"0xEDEA", "42", "0"}},
+	}
+	combinations, err := New(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	it, err := NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := it.Len(); n != len(combinations) {
+		t.Fatalf("expected %d combinations, got %d", len(combinations), n)
+	}
+	var itCombinations []Combination
+	for {
+		c, ok := it.Next()
+		if !ok {
+			break
+		}
+		itCombinations = append(itCombinations, c)
+	}
+	if !reflect.DeepEqual(combinations, itCombinations) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", combinations, itCombinations)
+	}
+	if c, ok := it.Next(); ok {
+		t.Errorf("expected exhausted iterator, got %#v", c)
+	}
+}
+
+func TestWriteIterator(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart"`, `"Tile"`}},
+		{Name: "figure", Values: []string{`"Jack"`, `"Queen"`}},
+	}
+	it, err := NewIterator(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteIterator(&buf, it); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{card: "Heart", figure: "Jack"},
+{card: "Heart", figure: "Queen"},
+{card: "Tile", figure: "Jack"},
+{card: "Tile", figure: "Queen"},
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, output)
+	}
+}
+
+func TestIteratorNoSets(t *testing.T) {
+	it, err := NewIterator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := it.Next(); ok {
+		t.Errorf("expected no combinations, got %#v", c)
+	}
+}
+
+func TestIteratorErrNoValues(t *testing.T) {
+	_, err := NewIterator([]Set{
+		{Name: "x", Values: []string{"0", "1"}},
+		{Name: "y", Values: []string{}},
+	})
+	if err != ErrSetNoValues {
+		t.Errorf("expected %v, got %v", ErrSetNoValues, err)
+	}
+}
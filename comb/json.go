@@ -0,0 +1,107 @@
+package comb
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/constant"
+	"go/token"
+	"io"
+	"math"
+)
+
+// WriteJSON writes the combinations yielded by it to w as a JSON array of objects, one per line,
+// with the set names as keys, in order.
+//
+// Values which are Go literals are decoded to the matching JSON type: strings, numbers and booleans.
+// Other values, such as function calls, are written as strings.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteJSON(w io.Writer, it Iterator) error {
+	sep := "[\n"
+	for {
+		c, ok := it.Next()
+		if !ok {
+			break
+		}
+		b, err := marshalJSON(c)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		sep = ",\n"
+	}
+	if sep == "[\n" {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(w, "\n]\n")
+	return err
+}
+
+// WriteJSONLines writes the combinations yielded by it to w as JSON Lines: one JSON object per line,
+// like WriteJSON.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteJSONLines(w io.Writer, it Iterator) error {
+	for {
+		c, ok := it.Next()
+		if !ok {
+			return nil
+		}
+		b, err := marshalJSON(c)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+}
+
+// marshalJSON returns the JSON object of c, keeping the order of its elements.
+func marshalJSON(c Combination) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, e := range c {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(e.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(jsonValue(e.Value))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonValue returns the value to encode in JSON for the value v of an element.
+func jsonValue(v string) interface{} {
+	kind, val := parseLiteral(v)
+	switch kind {
+	case token.STRING:
+		return constant.StringVal(val)
+	case token.INT:
+		return json.Number(val.ExactString())
+	case token.FLOAT:
+		// most decimals aren't exact float64s, e.g 0.1
+		if f, _ := constant.Float64Val(val); !math.IsInf(f, 0) {
+			return f
+		}
+	case token.IDENT:
+		return constant.BoolVal(val)
+	}
+	return v
+}
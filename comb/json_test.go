@@ -0,0 +1,77 @@
+package comb
+
+import (
+	"bytes"
+	"testing"
+)
+
+var jsonSets = []Set{
+	{Name: "card", Values: []string{`"Heart"`, "`Tile`"}},
+	{Name: "n", Values: []string{"0x10", "-2.5"}},
+	{Name: "ok", Values: []string{"true"}},
+	{Name: "status", Values: []string{"http.StatusOK"}},
+}
+
+func TestWriteJSON(t *testing.T) {
+	it, err := NewIterator(jsonSets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, it); err != nil {
+		t.Fatal(err)
+	}
+	expected := `[
+{"card":"Heart","n":16,"ok":true,"status":"http.StatusOK"},
+{"card":"Heart","n":-2.5,"ok":true,"status":"http.StatusOK"},
+{"card":"Tile","n":16,"ok":true,"status":"http.StatusOK"},
+{"card":"Tile","n":-2.5,"ok":true,"status":"http.StatusOK"}
+]
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, SliceIterator(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if output := buf.String(); output != "[]\n" {
+		t.Errorf("expected an empty array, got %s", output)
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	tests := []struct {
+		v        string
+		expected interface{}
+	}{
+		{v: "0.1", expected: 0.1},
+		{v: "1.5", expected: 1.5},
+		{v: "1e400", expected: "1e400"},
+	}
+	for _, test := range tests {
+		if v := jsonValue(test.v); v != test.expected {
+			t.Errorf("%s: expected %#v, got %#v", test.v, test.expected, v)
+		}
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	it, err := NewIterator(jsonSets[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteJSONLines(&buf, it); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"card":"Heart","n":16}
+{"card":"Heart","n":-2.5}
+{"card":"Tile","n":16}
+{"card":"Tile","n":-2.5}
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
@@ -0,0 +1,147 @@
+package comb
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+)
+
+// ErrValueSyntax represents an error when a value of a set with a type isn't a Go expression.
+var ErrValueSyntax = errors.New("set value is not a Go expression")
+
+// ErrValueType represents an error when a value of a set with a type is a literal which can't be of that type.
+var ErrValueType = errors.New("set value doesn't match its type")
+
+// intBits holds the size of the integer types.
+var intBits = map[string]int{
+	"int": 64, "int8": 8, "int16": 16, "int32": 32, "int64": 64, "rune": 32,
+	"uint": 64, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64, "uintptr": 64, "byte": 8,
+}
+
+// parseLiteral parses v if it's a Go literal: a basic literal, possibly signed, or true or false.
+// It returns the kind of the literal, token.IDENT for true and false, and its value,
+// or token.ILLEGAL if v isn't a literal.
+func parseLiteral(v string) (token.Token, constant.Value) {
+	expr, err := parser.ParseExpr(v)
+	if err != nil {
+		return token.ILLEGAL, nil
+	}
+	var op token.Token
+	if u, ok := expr.(*ast.UnaryExpr); ok && (u.Op == token.SUB || u.Op == token.ADD) {
+		op, expr = u.Op, u.X
+	}
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		val := constant.MakeFromLiteral(x.Value, x.Kind, 0)
+		if val.Kind() == constant.Unknown {
+			return token.ILLEGAL, nil
+		}
+		if op != token.ILLEGAL {
+			if x.Kind == token.STRING {
+				return token.ILLEGAL, nil
+			}
+			val = constant.UnaryOp(op, val, 0)
+		}
+		return x.Kind, val
+	case *ast.Ident:
+		if op == token.ILLEGAL && (x.Name == "true" || x.Name == "false") {
+			return token.IDENT, constant.MakeBool(x.Name == "true")
+		}
+	}
+	return token.ILLEGAL, nil
+}
+
+// literalType returns the default Go type of v if it's a literal: string, int, float64, complex128, rune or bool.
+// It returns "" if v isn't a literal.
+func literalType(v string) string {
+	kind, _ := parseLiteral(v)
+	switch kind {
+	case token.STRING:
+		return "string"
+	case token.INT:
+		return "int"
+	case token.FLOAT:
+		return "float64"
+	case token.IMAG:
+		return "complex128"
+	case token.CHAR:
+		return "rune"
+	case token.IDENT:
+		return "bool"
+	}
+	return ""
+}
+
+// typedValue returns v as a value of a set of type typ, or v itself if typ is empty.
+// If typ is string, v is quoted if it wasn't in the sets and isn't a string literal, e.g alice is "alice".
+// Otherwise, v must be a Go expression, and if it's a literal, one which can be of type typ
+// when typ is a predeclared boolean, numeric or string type.
+//
+// It returns an error, ErrValueSyntax if v isn't a Go expression or ErrValueType if it's a literal of another type.
+func typedValue(typ, v string, quoted bool) (string, error) {
+	if typ == "" {
+		return v, nil
+	}
+	kind, val := parseLiteral(v)
+	if typ == "string" && !quoted && kind != token.STRING {
+		return strconv.Quote(v), nil
+	}
+	if kind == token.ILLEGAL {
+		if _, err := parser.ParseExpr(v); err != nil {
+			return "", fmt.Errorf("%s: %w", v, ErrValueSyntax)
+		}
+		return v, nil
+	}
+	if !literalOfType(typ, kind, val) {
+		return "", fmt.Errorf("%s is not a valid %s: %w", v, typ, ErrValueType)
+	}
+	return v, nil
+}
+
+// literalOfType reports whether the literal val of kind, as returned by parseLiteral, can be of type typ.
+// It reports true for the types which aren't predeclared boolean, numeric or string types.
+func literalOfType(typ string, kind token.Token, val constant.Value) bool {
+	numeric := kind == token.INT || kind == token.FLOAT || kind == token.CHAR
+	switch typ {
+	case "string":
+		return kind == token.STRING
+	case "bool":
+		return kind == token.IDENT
+	case "float32":
+		if !numeric {
+			return false
+		}
+		f, _ := constant.Float32Val(val)
+		return !math.IsInf(float64(f), 0)
+	case "float64":
+		if !numeric {
+			return false
+		}
+		f, _ := constant.Float64Val(val)
+		return !math.IsInf(f, 0)
+	case "complex64", "complex128":
+		return numeric || kind == token.IMAG
+	}
+	bits, ok := intBits[typ]
+	if !ok {
+		return true
+	}
+	if !numeric {
+		return false
+	}
+	i := constant.ToInt(val)
+	if i.Kind() != constant.Int {
+		return false
+	}
+	if typ[0] == 'u' || typ == "byte" {
+		u, exact := constant.Uint64Val(i)
+		return exact && (bits == 64 || u < 1<<bits)
+	}
+	n, exact := constant.Int64Val(i)
+	return exact && (bits == 64 || (n >= -1<<(bits-1) && n < 1<<(bits-1)))
+}
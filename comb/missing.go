@@ -0,0 +1,146 @@
+package comb
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+)
+
+// Missing returns the combinations of sets allowed by rules which aren't in rows, in the order of New.
+// A row holds a combination if it has the same value for each set, whatever its other elements.
+// Values are compared as Go expressions, so "f(1,2)" is the same as "f(1, 2)".
+//
+// It returns an error if the combinations of sets can't be iterated, see NewIterator.
+func Missing(sets []Set, rows []Combination, rules ...Rule) ([]Combination, error) {
+	it, err := NewIterator(sets, rules...)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool, len(rows))
+	for _, row := range rows {
+		indices, ok := rowIndices(sets, row)
+		if !ok {
+			continue
+		}
+		i, err := RankIndices(sets, indices)
+		if err != nil {
+			return nil, err
+		}
+		seen[i] = true
+	}
+	var missing []Combination
+	for {
+		c, ok := it.Next()
+		if !ok {
+			break
+		}
+		if !seen[it.Index()] {
+			missing = append(missing, c)
+		}
+	}
+	return missing, nil
+}
+
+// MissingTuples returns the tuples of strength values from different sets which aren't in any of rows,
+// as partial combinations holding only the sets of the tuple. Tuples which can't be in
+// any combination allowed by rules are left out, as with NewCovering.
+// Values are compared as with Missing, and a row holds the tuples whose values are all in the sets,
+// even if it has other values which aren't.
+//
+// It returns an error, ErrSetNoValues if one of the sets provided has no values,
+// ErrInvalidStrength if strength is out of range or a *RuleError if a rule refers to an unknown set.
+func MissingTuples(sets []Set, strength int, rows []Combination, rules ...Rule) ([]Combination, error) {
+	for _, set := range sets {
+		if len(set.Values) == 0 {
+			return nil, ErrSetNoValues
+		}
+	}
+	if strength < 1 || strength > len(sets) {
+		return nil, ErrInvalidStrength
+	}
+	rules, err := checkRules(sets, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	cov := newCoverage(sets, strength)
+	cov.rules = rules
+	for _, row := range rows {
+		cov.cover(valueIndices(sets, row))
+	}
+	var missing []Combination
+	row := make([]int, len(sets))
+	for _, g := range cov.groups {
+		for i, covered := range g.covered {
+			if covered {
+				continue
+			}
+			for k := range row {
+				row[k] = -1
+			}
+			n := i
+			for j := len(g.sets) - 1; j > -1; j-- {
+				k := g.sets[j]
+				row[k] = n % cov.sizes[k]
+				n /= cov.sizes[k]
+			}
+			c := make(Combination, 0, len(g.sets))
+			for _, k := range g.sets {
+				c = append(c, Element{Name: sets[k].Name, Value: sets[k].Values[row[k]]})
+			}
+			if cov.allows(row) && cov.search(row, 0) {
+				missing = append(missing, c)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// rowIndices returns the indices of the values of row in each of sets.
+// It reports false if row lacks a set or has a value which isn't in it.
+func rowIndices(sets []Set, row Combination) ([]int, bool) {
+	indices := valueIndices(sets, row)
+	for _, i := range indices {
+		if i == -1 {
+			return nil, false
+		}
+	}
+	return indices, true
+}
+
+// valueIndices returns the index of the value of row in each of sets,
+// or -1 for a set which row lacks or whose value isn't in it.
+func valueIndices(sets []Set, row Combination) []int {
+	indices := make([]int, len(sets))
+	for k, set := range sets {
+		indices[k] = -1
+		for _, e := range row {
+			if e.Name != set.Name {
+				continue
+			}
+			v := normalizeValue(e.Value)
+			for i, sv := range set.Values {
+				if normalizeValue(sv) == v {
+					indices[k] = i
+					break
+				}
+			}
+			break
+		}
+	}
+	return indices
+}
+
+// normalizeValue returns v formatted as a Go expression, or v itself if it isn't one.
+func normalizeValue(v string) string {
+	expr, err := parser.ParseExpr(v)
+	if err != nil {
+		return v
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		return v
+	}
+	return buf.String()
+}
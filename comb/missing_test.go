@@ -0,0 +1,148 @@
+package comb
+
+import (
+	"reflect"
+	"testing"
+)
+
+var missingSets = []Set{
+	{Name: "card", Values: []string{`"Heart"`, `"Tile"`}},
+	{Name: "n", Values: []string{"1", "f(1, 2)"}},
+	{Name: "ok", Values: []string{"true", "false"}},
+}
+
+func TestMissing(t *testing.T) {
+	rows := []Combination{
+		{{Name: "n", Value: "f(1,2)"}, {Name: "card", Value: `"Heart"`}, {Name: "ok", Value: "true"}, {Name: "other", Value: "0"}},
+		{{Name: "card", Value: `"Heart"`}, {Name: "n", Value: "1"}, {Name: "ok", Value: "true"}},
+		{{Name: "card", Value: `"Heart"`}, {Name: "n", Value: "1"}, {Name: "ok", Value: "false"}},
+		{{Name: "card", Value: `"Tile"`}, {Name: "n", Value: "1"}},
+		{{Name: "card", Value: `"Pike"`}, {Name: "n", Value: "1"}, {Name: "ok", Value: "true"}},
+	}
+	rule, err := ParseRule(`!exclude card == "\"Tile\"" && ok == false`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := Missing(missingSets, rows, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Combination{
+		{{Name: "card", Value: `"Heart"`}, {Name: "n", Value: "f(1, 2)"}, {Name: "ok", Value: "false"}},
+		{{Name: "card", Value: `"Tile"`}, {Name: "n", Value: "1"}, {Name: "ok", Value: "true"}},
+		{{Name: "card", Value: `"Tile"`}, {Name: "n", Value: "f(1, 2)"}, {Name: "ok", Value: "true"}},
+	}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, missing)
+	}
+
+	all, err := New(missingSets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err = Missing(missingSets, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing combinations, got %#v", missing)
+	}
+}
+
+func TestMissingTuples(t *testing.T) {
+	rows, err := NewCovering(missingSets, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := MissingTuples(missingSets, 2, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing tuples, got %#v", missing)
+	}
+
+	rows = []Combination{
+		{{Name: "card", Value: `"Heart"`}, {Name: "n", Value: "1"}, {Name: "ok", Value: "true"}},
+		{{Name: "card", Value: `"Tile"`}, {Name: "n", Value: "f(1,2)"}, {Name: "ok", Value: "true"}},
+	}
+	rule, err := ParseRule(`!exclude card == "\"Tile\"" && ok == false`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err = MissingTuples(missingSets, 2, rows, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Combination{
+		{{Name: "card", Value: `"Heart"`}, {Name: "n", Value: "f(1, 2)"}},
+		{{Name: "card", Value: `"Tile"`}, {Name: "n", Value: "1"}},
+		{{Name: "card", Value: `"Heart"`}, {Name: "ok", Value: "false"}},
+		{{Name: "n", Value: "1"}, {Name: "ok", Value: "false"}},
+		{{Name: "n", Value: "f(1, 2)"}, {Name: "ok", Value: "false"}},
+	}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, missing)
+	}
+
+	if _, err := MissingTuples(missingSets, 4, rows); err != ErrInvalidStrength {
+		t.Errorf("expected %v, got %v", ErrInvalidStrength, err)
+	}
+}
+
+func TestMissingTuplesUnknownValue(t *testing.T) {
+	sets := []Set{
+		{Name: "a", Values: []string{"1", "2"}},
+		{Name: "b", Values: []string{"1", "2"}},
+		{Name: "c", Values: []string{"x"}},
+	}
+	rows := []Combination{
+		{{Name: "a", Value: "1"}, {Name: "b", Value: "1"}, {Name: "c", Value: "x"}},
+		{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}, {Name: "c", Value: "x"}},
+		{{Name: "a", Value: "2"}, {Name: "b", Value: "1"}, {Name: "c", Value: "x"}},
+		// c isn't in the sets, but the tuple of a and b is.
+		{{Name: "a", Value: "2"}, {Name: "b", Value: "2"}, {Name: "c", Value: "y"}},
+	}
+	missing, err := MissingTuples(sets, 2, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing tuples, got %#v", missing)
+	}
+}
+
+func TestMissingTuplesWithRules(t *testing.T) {
+	sets := []Set{
+		{Name: "a", Values: []string{"1", "2", "3", "4", "5", "6"}},
+		{Name: "b", Values: []string{"1", "2", "3", "4", "5", "6"}},
+		{Name: "c", Values: []string{"p", "q"}},
+		{Name: "d", Values: []string{"p", "q"}},
+	}
+	var rules []Rule
+	for _, text := range []string{"!exclude c==p && d==p", "!exclude c==p && d==q"} {
+		rule, err := ParseRule(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, rule)
+	}
+	missing, err := MissingTuples(sets, 2, nil, rules...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// all pairs are allowed with c=q, and none with c=p
+	counts := make(map[string]int)
+	for _, c := range missing {
+		counts[c[0].Name+c[1].Name]++
+		for _, e := range c {
+			if e.Name == "c" && e.Value == "p" {
+				t.Errorf("%v can't be in an allowed combination", c)
+			}
+		}
+	}
+	expected := map[string]int{"ab": 36, "ac": 6, "ad": 12, "bc": 6, "bd": 12, "cd": 2}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected %v missing tuples, got %v", expected, counts)
+	}
+}
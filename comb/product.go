@@ -0,0 +1,170 @@
+package comb
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// Dimension is a named list of Go values, the runtime counterpart of a Set.
+// Values[T], created with Dim, is the usual implementation.
+type Dimension interface {
+	// Name returns the name of the dimension.
+	Name() string
+	// Len returns the number of values of the dimension.
+	Len() int
+	// Value returns the value at index i.
+	Value(i int) any
+}
+
+// Values is a Dimension holding values of type T.
+type Values[T any] struct {
+	name   string
+	values []T
+}
+
+// Dim returns a dimension named name, holding values.
+func Dim[T any](name string, values []T) Values[T] {
+	return Values[T]{name: name, values: values}
+}
+
+// Name implements Dimension.
+func (d Values[T]) Name() string {
+	return d.name
+}
+
+// Len implements Dimension.
+func (d Values[T]) Len() int {
+	return len(d.values)
+}
+
+// Value implements Dimension.
+func (d Values[T]) Value(i int) any {
+	return d.values[i]
+}
+
+// At returns the value at index i.
+func (d Values[T]) At(i int) T {
+	return d.values[i]
+}
+
+// Product returns an iterator over the combinations of the values of dims, in the same order as New:
+// the values of the last dimension vary the fastest. Each combination is a struct S with the field of each dimension
+// set to its value, and the others left to their zero value, e.g:
+//
+//	type test struct {
+//		Card   Card
+//		N      int
+//		Result string
+//	}
+//	for tt := range comb.Product[test](comb.Dim("card", cards), comb.Dim("n", []int{1, 2})) {
+//		...
+//	}
+//
+// The field of a dimension is the one with a comb tag holding its name, e.g `comb:"card"`,
+// or else the one whose name is the same as the dimension's, ignoring case.
+// It must be exported, and the values of the dimension must be assignable to it.
+//
+// Product panics if S isn't a struct or if a dimension has no field.
+// The iterator panics if a value isn't assignable to its field.
+func Product[S any](dims ...Dimension) iter.Seq[S] {
+	typ := reflect.TypeFor[S]()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("comb: Product of %v, which isn't a struct", typ))
+	}
+	fields := make([]int, len(dims))
+	for k, d := range dims {
+		field, ok := dimField(typ, d.Name())
+		if !ok {
+			panic(fmt.Sprintf("comb: %v has no field for dimension %q", typ, d.Name()))
+		}
+		if !field.IsExported() {
+			panic(fmt.Sprintf("comb: field %s of %v for dimension %q is unexported", field.Name, typ, d.Name()))
+		}
+		fields[k] = field.Index[0]
+	}
+	return func(yield func(S) bool) {
+		for indices := range productIndices(dims) {
+			var s S
+			v := reflect.ValueOf(&s).Elem()
+			for k, d := range dims {
+				setField(v.Field(fields[k]), d, d.Value(indices[k]))
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Product2 returns an iterator over the pairs of values of a and b, in the same order as Product.
+func Product2[A, B any](a Values[A], b Values[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		for indices := range productIndices([]Dimension{a, b}) {
+			if !yield(a.At(indices[0]), b.At(indices[1])) {
+				return
+			}
+		}
+	}
+}
+
+// productIndices returns an iterator over the indices of the values of each of dims, in mixed-radix order.
+// The slice yielded is reused between iterations.
+func productIndices(dims []Dimension) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		if len(dims) == 0 {
+			return
+		}
+		for _, d := range dims {
+			if d.Len() == 0 {
+				return
+			}
+		}
+		indices := make([]int, len(dims))
+		for {
+			if !yield(indices) {
+				return
+			}
+			k := len(indices) - 1
+			for ; k > -1; k-- {
+				indices[k]++
+				if indices[k] < dims[k].Len() {
+					break
+				}
+				indices[k] = 0
+			}
+			if k == -1 {
+				return
+			}
+		}
+	}
+}
+
+// dimField returns the field of typ for the dimension named name.
+func dimField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Tag.Get("comb") == name {
+			return field, true
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Tag.Get("comb") == "" && strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// setField sets field to value, a value of d.
+func setField(field reflect.Value, d Dimension, value any) {
+	if value == nil {
+		field.SetZero()
+		return
+	}
+	v := reflect.ValueOf(value)
+	if !v.Type().AssignableTo(field.Type()) {
+		panic(fmt.Sprintf("comb: value %v of dimension %q is a %v, not assignable to %v", value, d.Name(), v.Type(), field.Type()))
+	}
+	field.Set(v)
+}
@@ -0,0 +1,113 @@
+package comb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type card string
+
+func TestProduct(t *testing.T) {
+	type test struct {
+		Card   card
+		Count  int `comb:"n"`
+		N      string
+		Result bool
+	}
+	var tests []test
+	for tt := range Product[test](Dim("card", []card{"Heart", "Tile"}), Dim("n", []int{1, 2, 3})) {
+		tests = append(tests, tt)
+	}
+	expected := []test{
+		{Card: "Heart", Count: 1},
+		{Card: "Heart", Count: 2},
+		{Card: "Heart", Count: 3},
+		{Card: "Tile", Count: 1},
+		{Card: "Tile", Count: 2},
+		{Card: "Tile", Count: 3},
+	}
+	if !reflect.DeepEqual(tests, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, tests)
+	}
+
+	var n int
+	for range Product[test](Dim("card", []card{"Heart", "Tile"}), Dim("n", []int{1, 2, 3})) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("expected to stop after 2 combinations, got %d", n)
+	}
+
+	for tt := range Product[test](Dim("card", []card{"Heart"}), Dim("n", []int{})) {
+		t.Errorf("expected no combinations, got %#v", tt)
+	}
+}
+
+func TestProductSameOrderAsNew(t *testing.T) {
+	type test struct {
+		S1, S2, I3 string
+	}
+	var dims []Dimension
+	for _, set := range rankSets {
+		dims = append(dims, Dim(set.Name, set.Values))
+	}
+	combinations, err := New(rankSets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var i int
+	for tt := range Product[test](dims...) {
+		expected := test{S1: combinations[i][0].Value, S2: combinations[i][1].Value, I3: combinations[i][2].Value}
+		if tt != expected {
+			t.Errorf("index %d: expected %#v, got %#v", i, expected, tt)
+		}
+		i++
+	}
+	if i != len(combinations) {
+		t.Errorf("expected %d combinations, got %d", len(combinations), i)
+	}
+}
+
+func TestProductPanics(t *testing.T) {
+	type test struct {
+		Card card
+		n    int
+	}
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{name: "not a struct", f: func() { Product[int](Dim("n", []int{1})) }},
+		{name: "no field", f: func() { Product[test](Dim("figure", []string{"Jack"})) }},
+		{name: "unexported", f: func() { Product[test](Dim("n", []int{1})) }},
+		{name: "not assignable", f: func() {
+			for range Product[test](Dim("card", []string{"Heart"})) {
+			}
+		}},
+	}
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", test.name)
+				}
+			}()
+			test.f()
+		}()
+	}
+}
+
+func TestProduct2(t *testing.T) {
+	var got []string
+	for c, n := range Product2(Dim("card", []card{"Heart", "Tile"}), Dim("n", []int{1, 2})) {
+		got = append(got, fmt.Sprintf("%s/%d", c, n))
+	}
+	expected := []string{"Heart/1", "Heart/2", "Tile/1", "Tile/2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
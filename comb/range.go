@@ -0,0 +1,142 @@
+package comb
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRange represents an error when a range of values has a zero step or bounds of different kinds.
+var ErrInvalidRange = errors.New("invalid range")
+
+// ErrRangeTooLarge represents an error when a range has more than maxRangeValues values.
+var ErrRangeTooLarge = errors.New("range has too many values")
+
+// maxRangeValues is the maximum number of values of a range.
+const maxRangeValues = 1 << 20
+
+// expandRange returns the values of v if it's a range, or nil if it isn't.
+//
+// A range is written start..end or start..end..step, with integer bounds, e.g 1..10, -5..5, 0x00..0xFF..0x10,
+// or rune literals bounds, e.g 'a'..'f'. The end is included if the step reaches it.
+// Integer values keep the base, the case and the zero padding of the bounds.
+// As in Go, an integer with a leading zero is octal, e.g 07..010 is 07 010, and 08 isn't an integer.
+// If end is lower than start, the values go down from start.
+// A range can't have more than maxRangeValues values.
+func expandRange(v string) ([]string, error) {
+	parts := strings.Split(v, "..")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, nil
+	}
+	start, ok1 := parseRangeBound(parts[0])
+	end, ok2 := parseRangeBound(parts[1])
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+	if start.char != end.char {
+		return nil, ErrInvalidRange
+	}
+	step := int64(1)
+	if len(parts) == 3 {
+		s, ok := parseRangeBound(parts[2])
+		if !ok || s.char || s.n <= 0 {
+			return nil, ErrInvalidRange
+		}
+		step = s.n
+	}
+	// the difference of two int64 always fits in an uint64
+	span := uint64(end.n - start.n)
+	if end.n < start.n {
+		span = uint64(start.n - end.n)
+	}
+	count := span/uint64(step) + 1
+	if count > maxRangeValues {
+		return nil, ErrRangeTooLarge
+	}
+	if end.n < start.n {
+		step = -step
+	}
+	format := start
+	format.upper = start.upper || end.upper
+	values := make([]string, count)
+	n := start.n
+	for i := range values {
+		if i > 0 {
+			n += step
+		}
+		values[i] = format.format(n)
+	}
+	return values, nil
+}
+
+// rangeBound is a bound of a range, with the format it's written in.
+type rangeBound struct {
+	n      int64
+	char   bool
+	prefix string
+	base   int
+	width  int
+	upper  bool
+}
+
+func parseRangeBound(s string) (rangeBound, bool) {
+	if strings.HasPrefix(s, "'") {
+		r, _, tail, err := strconv.UnquoteChar(strings.TrimPrefix(s, "'"), '\'')
+		if err != nil || tail != "'" {
+			return rangeBound{}, false
+		}
+		return rangeBound{n: int64(r), char: true}, true
+	}
+	b := rangeBound{base: 10}
+	digits := strings.TrimPrefix(s, "-")
+	neg := len(digits) < len(s)
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			b.base = 16
+		case 'o', 'O':
+			b.base = 8
+		case 'b', 'B':
+			b.base = 2
+		}
+		if b.base != 10 {
+			b.prefix, digits = digits[:2], digits[2:]
+		}
+	}
+	if b.base == 10 && len(digits) > 1 && digits[0] == '0' {
+		b.base, b.prefix, digits = 8, "0", digits[1:]
+	}
+	u, err := strconv.ParseUint(digits, b.base, 64)
+	if err != nil || digits == "" || digits[0] == '+' || u > 1<<63 || (!neg && u == 1<<63) {
+		return rangeBound{}, false
+	}
+	if len(digits) > 1 && digits[0] == '0' {
+		b.width = len(digits)
+	}
+	b.upper = strings.ToLower(digits) != digits
+	b.n = int64(u)
+	if neg {
+		b.n = -b.n
+	}
+	return b, true
+}
+
+// format writes n like b is written.
+func (b rangeBound) format(n int64) string {
+	if b.char {
+		return strconv.QuoteRune(rune(n))
+	}
+	var sign string
+	u := uint64(n)
+	if n < 0 {
+		sign, u = "-", uint64(-n)
+	}
+	digits := strconv.FormatUint(u, b.base)
+	if b.upper {
+		digits = strings.ToUpper(digits)
+	}
+	if len(digits) < b.width {
+		digits = strings.Repeat("0", b.width-len(digits)) + digits
+	}
+	return sign + b.prefix + digits
+}
@@ -0,0 +1,88 @@
+package comb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandRange(t *testing.T) {
+	tests := []struct {
+		v      string
+		values []string
+	}{
+		{v: "1..5", values: []string{"1", "2", "3", "4", "5"}},
+		{v: "0..100..25", values: []string{"0", "25", "50", "75", "100"}},
+		{v: "0..10..4", values: []string{"0", "4", "8"}},
+		{v: "3..-1", values: []string{"3", "2", "1", "0", "-1"}},
+		{v: "07..010", values: []string{"07", "010"}},
+		{v: "00..10..4", values: []string{"00", "04", "010"}},
+		{v: "0000..0010..4", values: []string{"0000", "0004", "0010"}},
+		{v: "0..10..5", values: []string{"0", "5", "10"}},
+		{v: "08..11", values: nil},
+		{v: "0x00..0xFF..0x40", values: []string{"0x00", "0x40", "0x80", "0xC0"}},
+		{v: "0xa..0xc", values: []string{"0xa", "0xb", "0xc"}},
+		{v: "0b0..0b11", values: []string{"0b0", "0b1", "0b10", "0b11"}},
+		{v: "'a'..'f'..2", values: []string{"'a'", "'c'", "'e'"}},
+		{v: "5..5", values: []string{"5"}},
+		{v: "9223372036854775806..9223372036854775807", values: []string{"9223372036854775806", "9223372036854775807"}},
+		{v: "-9223372036854775807..-9223372036854775808", values: []string{"-9223372036854775807", "-9223372036854775808"}},
+		{v: "-9223372036854775807..9223372036854775807..9223372036854775807", values: []string{"-9223372036854775807", "0", "9223372036854775807"}},
+		{v: "Heart", values: nil},
+		{v: "a..b", values: nil},
+		{v: "1.5..2", values: nil},
+		{v: "[...]int{1}", values: nil},
+		{v: "1..2..3..4", values: nil},
+	}
+	for _, test := range tests {
+		values, err := expandRange(test.v)
+		if err != nil {
+			t.Errorf("%s: %v", test.v, err)
+			continue
+		}
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%s: expected %#v, got %#v", test.v, test.values, values)
+		}
+	}
+}
+
+func TestExpandRangeErrors(t *testing.T) {
+	for _, v := range []string{"1..5..0", "1..5..-1", "'a'..5", "1..5..'a'"} {
+		if _, err := expandRange(v); err != ErrInvalidRange {
+			t.Errorf("%s: expected %v, got %v", v, ErrInvalidRange, err)
+		}
+	}
+}
+
+func TestExpandRangeTooLarge(t *testing.T) {
+	for _, v := range []string{"0..3000000000", "-9223372036854775807..9223372036854775807"} {
+		if _, err := expandRange(v); err != ErrRangeTooLarge {
+			t.Errorf("%s: expected %v, got %v", v, ErrRangeTooLarge, err)
+		}
+	}
+}
+
+func TestParseSetsRanges(t *testing.T) {
+	sets, _, err := ParseSets(strings.NewReader(`size: 0..3 "4..5" 10..30..10
+letter: 'x'..'z'
+bad: 1 2 1..2..0`))
+	if err == nil {
+		t.Fatal("expected a non-nil error, got nil")
+	}
+	if perr, ok := err.(*ParseError); !ok || perr.Line != 3 || perr.Column != 10 {
+		t.Errorf("expected an error at 3:10, got %v", err)
+	}
+
+	sets, _, err = ParseSets(strings.NewReader(`size: 0..3 "4..5" 10..30..10
+letter: 'x'..'z'`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Set{
+		{Name: "size", Values: []string{"0", "1", "2", "3", "4..5", "10", "20", "30"}},
+		{Name: "letter", Values: []string{"'x'", "'y'", "'z'"}},
+	}
+	if !reflect.DeepEqual(sets, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, sets)
+	}
+}
@@ -0,0 +1,116 @@
+package comb
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// ErrIndexOutOfRange represents an error when an index is not the one of a combination.
+var ErrIndexOutOfRange = errors.New("combination index out of range")
+
+// ErrNotInSets represents an error when a combination has a name or a value not found in the sets.
+var ErrNotInSets = errors.New("combination is not made from the sets")
+
+// ErrInvalidCombination represents an error when a combination can't be parsed.
+var ErrInvalidCombination = errors.New("invalid combination")
+
+// Unrank returns the combination at index i in the order of New, without creating the ones before it.
+//
+// It returns an error, ErrIndexOutOfRange if there is no combination at i
+// or an *OverflowError if there are too many combinations to index them.
+func Unrank(sets []Set, i int) (Combination, error) {
+	n, err := numCombinations(sets)
+	if err != nil {
+		return nil, err
+	}
+	if i < 0 || i >= n {
+		return nil, ErrIndexOutOfRange
+	}
+	c := make(Combination, len(sets))
+	for k := len(sets) - 1; k > -1; k-- {
+		l := len(sets[k].Values)
+		c[k] = Element{Name: sets[k].Name, Value: sets[k].Values[i%l]}
+		i /= l
+	}
+	return c, nil
+}
+
+// Rank returns the index of c in the order of New. It is the inverse of Unrank.
+//
+// The elements of c are matched to the sets by name, in any order.
+// Values are compared as Go expressions, so "f(1,2)" is the same as "f(1, 2)".
+// It returns an error, ErrNotInSets if c has no element for a set or a value not found in it.
+func Rank(sets []Set, c Combination) (int, error) {
+	indices, ok := rowIndices(sets, c)
+	if !ok {
+		return 0, ErrNotInSets
+	}
+	return RankIndices(sets, indices)
+}
+
+// RankIndices returns the index in the order of New of the combination made of the value at indices[k] of each sets[k].
+//
+// It returns an error, ErrNotInSets if an index is not the one of a value of its set
+// or an *OverflowError if there are too many combinations to index them.
+func RankIndices(sets []Set, indices []int) (int, error) {
+	if len(indices) != len(sets) {
+		return 0, ErrNotInSets
+	}
+	if _, err := numCombinations(sets); err != nil {
+		return 0, err
+	}
+	var i int
+	for k, set := range sets {
+		if len(set.Values) == 0 {
+			return 0, ErrSetNoValues
+		}
+		if indices[k] < 0 || indices[k] >= len(set.Values) {
+			return 0, ErrNotInSets
+		}
+		i = i*len(set.Values) + indices[k]
+	}
+	return i, nil
+}
+
+// ParseCombination parses a combination written as by WriteCombinations, e.g:
+//
+//	{card: "Heart", figure: "Jack"},
+//
+// The values are the source text of the values of the literal.
+// It returns an error, ErrInvalidCombination if s isn't a keyed composite literal.
+func ParseCombination(s string) (Combination, error) {
+	src := "struct{}" + strings.TrimSuffix(strings.TrimSpace(s), ",")
+	fset := token.NewFileSet()
+	expr, err := parser.ParseExprFrom(fset, "", src, 0)
+	if err != nil {
+		return nil, ErrInvalidCombination
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, ErrInvalidCombination
+	}
+	return combinationFromLit(fset, []byte(src), lit)
+}
+
+// combinationFromLit returns the combination of a keyed composite literal of src.
+// The values are the source text of the literal's values.
+func combinationFromLit(fset *token.FileSet, src []byte, lit *ast.CompositeLit) (Combination, error) {
+	var c Combination
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, ErrInvalidCombination
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil, ErrInvalidCombination
+		}
+		start := fset.Position(kv.Value.Pos()).Offset
+		end := fset.Position(kv.Value.End()).Offset
+		c = append(c, Element{Name: key.Name, Value: string(src[start:end])})
+	}
+	return c, nil
+}
@@ -0,0 +1,99 @@
+package comb
+
+import (
+	"reflect"
+	"testing"
+)
+
+var rankSets = []Set{
+	{Name: "S1", Values: []string{`"X"`, `"Y"`}},
+	{Name: "S2", Values: []string{`"µ"`, `"v"`}},
+	{Name: "I3", Values: []string{"0xEDEA", "42", "0"}},
+}
+
+func TestUnrankRank(t *testing.T) {
+	combinations, err := New(rankSets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range combinations {
+		c, err := Unrank(rankSets, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(c, expected) {
+			t.Errorf("index %d: expected %#v, got %#v", i, expected, c)
+		}
+		j, err := Rank(rankSets, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j != i {
+			t.Errorf("expected rank %d, got %d", i, j)
+		}
+	}
+}
+
+func TestUnrankOutOfRange(t *testing.T) {
+	for _, i := range []int{-1, 12, 100} {
+		if _, err := Unrank(rankSets, i); err != ErrIndexOutOfRange {
+			t.Errorf("index %d: expected %v, got %v", i, ErrIndexOutOfRange, err)
+		}
+	}
+}
+
+func TestRankNotInSets(t *testing.T) {
+	tests := []Combination{
+		{{Name: "S1", Value: `"X"`}, {Name: "S2", Value: `"v"`}},
+		{{Name: "S1", Value: `"X"`}, {Name: "S2", Value: `"v"`}, {Name: "I3", Value: "43"}},
+		{{Name: "S1", Value: `"X"`}, {Name: "S2", Value: `"v"`}, {Name: "I4", Value: "42"}},
+	}
+	for _, c := range tests {
+		if _, err := Rank(rankSets, c); err != ErrNotInSets {
+			t.Errorf("%v: expected %v, got %v", c, ErrNotInSets, err)
+		}
+	}
+}
+
+func TestRankGoExpressions(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart"`, "`Tile`"}},
+		{Name: "figure", Values: []string{"f(1,2)", "[]int{1,2}"}},
+	}
+	c, err := ParseCombination("{card: `Tile`, figure: []int{1, 2}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := Rank(sets, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 3 {
+		t.Errorf("expected rank 3, got %d", i)
+	}
+}
+
+func TestParseCombination(t *testing.T) {
+	c, err := ParseCombination(`{I3: 42, S1: "Y", S2: "µ"},`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Combination{
+		{Name: "I3", Value: "42"},
+		{Name: "S1", Value: `"Y"`},
+		{Name: "S2", Value: `"µ"`},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, c)
+	}
+	i, err := Rank(rankSets, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 7 {
+		t.Errorf("expected rank 7, got %d", i)
+	}
+	if _, err := ParseCombination(`{S1: "Y", "µ"}`); err != ErrInvalidCombination {
+		t.Errorf("expected %v, got %v", ErrInvalidCombination, err)
+	}
+}
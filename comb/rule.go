@@ -0,0 +1,436 @@
+package comb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrRuleSyntax represents an error when a rule isn't well formed.
+var ErrRuleSyntax = errors.New("invalid rule syntax")
+
+// ErrRuleUnknownSet represents an error when a rule refers to a set which doesn't exist.
+var ErrRuleUnknownSet = errors.New("rule refers to an unknown set")
+
+// ErrRuleUnknownValue represents an error when a rule compares a set with a value it doesn't have.
+var ErrRuleUnknownValue = errors.New("rule refers to a value not in its set")
+
+// RuleError records an error and the rule that caused it.
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %q: %v", e.Rule, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// Rule is a constraint which combinations must satisfy.
+//
+// A rule follows the syntax:
+//
+//	!exclude expr
+//	!require expr
+//
+// The ! can also be left out when the directive is followed by a colon, e.g require: expr,
+// so that exclude and require can't be set names.
+// An exclude rule drops the combinations for which expr is true, a require rule those for which it is false.
+// expr compares set values with == (or =) and !=, and combines comparisons with !, &&, || and -> (implication),
+// by increasing precedence: ->, ||, &&, !. Parentheses group expressions. For example:
+//
+//	!exclude card=="Heart" && figure=="Jack"
+//	require: os=windows -> arch!=arm
+//
+// Values are written like in a set, and compared with them after being unquoted.
+// With the type string, bare values are strings like in the set, e.g name==alice
+// for the set name string: alice bob.
+// When the rule is used with sets, each value must be one of its set, compared as Go expressions.
+type Rule struct {
+	// Text is the rule as written.
+	Text    string
+	exclude bool
+	expr    ruleExpr
+}
+
+// isRule reports whether line is a rule rather than a set:
+// whether it starts with ! or with require or exclude followed by a colon.
+func isRule(line string) bool {
+	if strings.HasPrefix(line, "!") {
+		return true
+	}
+	for _, directive := range []string{"exclude", "require"} {
+		if rest := strings.TrimPrefix(line, directive); rest != line && strings.HasPrefix(strings.TrimLeftFunc(rest, unicode.IsSpace), ":") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRule parses a rule from text.
+//
+// It returns the rule or a *RuleError wrapping ErrRuleSyntax.
+func ParseRule(text string) (Rule, error) {
+	r := Rule{Text: text}
+	directive := strings.TrimPrefix(text, "!")
+	i := strings.IndexFunc(directive, func(r rune) bool { return r == ':' || unicode.IsSpace(r) })
+	if i == -1 {
+		return r, &RuleError{Rule: text, Err: ErrRuleSyntax}
+	}
+	switch directive[:i] {
+	case "exclude":
+		r.exclude = true
+	case "require":
+	default:
+		return r, &RuleError{Rule: text, Err: fmt.Errorf("%w: unknown directive %q", ErrRuleSyntax, "!"+directive[:i])}
+	}
+	p := &ruleParser{s: strings.TrimPrefix(strings.TrimLeftFunc(directive[i:], unicode.IsSpace), ":")}
+	expr, err := p.parseExpr()
+	if err == nil && p.skipSpace() != "" {
+		err = p.errorf("unexpected %q", p.s)
+	}
+	if err != nil {
+		return r, &RuleError{Rule: text, Err: err}
+	}
+	r.expr = expr
+	return r, nil
+}
+
+// Allows reports whether c satisfies the rule.
+func (r Rule) Allows(c Combination) bool {
+	return r.allows(func(name string) (string, bool) {
+		for _, e := range c {
+			if e.Name == name {
+				return e.Value, true
+			}
+		}
+		return "", false
+	}) != ruleFalse
+}
+
+// allows evaluates the rule for a combination whose values may not all be known yet.
+func (r Rule) allows(lookup func(name string) (string, bool)) ruleValue {
+	v := r.expr.eval(lookup)
+	if r.exclude {
+		return v.not()
+	}
+	return v
+}
+
+// checkRules verifies that rules only refer to sets and to their values.
+// It returns rules bound to sets: each value is the value of its set it's equal to,
+// with the type of the set (see typedValue) and compared as a Go expression.
+//
+// It returns the rules or a *RuleError wrapping ErrRuleUnknownSet, ErrRuleUnknownValue,
+// or the error of a value which doesn't match the type of its set.
+func checkRules(sets []Set, rules []Rule) ([]Rule, error) {
+	if len(rules) == 0 {
+		return rules, nil
+	}
+	bound := make([]Rule, len(rules))
+	for i, r := range rules {
+		expr, err := r.expr.bind(sets)
+		if err != nil {
+			return nil, &RuleError{Rule: r.Text, Err: err}
+		}
+		bound[i] = r
+		bound[i].expr = expr
+	}
+	return bound, nil
+}
+
+// allowed reports whether c satisfies all rules.
+func allowed(rules []Rule, c Combination) bool {
+	for _, r := range rules {
+		if !r.Allows(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleNames returns the names of the sets rules refer to.
+func ruleNames(rules []Rule) map[string]bool {
+	names := make(map[string]bool)
+	for _, r := range rules {
+		// with no value known, every comparison is evaluated.
+		r.allows(func(name string) (string, bool) {
+			names[name] = true
+			return "", false
+		})
+	}
+	return names
+}
+
+// ruleValue is the value of an expression, which may be unknown when some values aren't known yet.
+type ruleValue int
+
+const (
+	ruleUnknown ruleValue = iota
+	ruleFalse
+	ruleTrue
+)
+
+func (v ruleValue) not() ruleValue {
+	switch v {
+	case ruleTrue:
+		return ruleFalse
+	case ruleFalse:
+		return ruleTrue
+	}
+	return ruleUnknown
+}
+
+type ruleExpr interface {
+	eval(lookup func(name string) (string, bool)) ruleValue
+	// bind returns a copy of the expression comparing values of sets.
+	bind(sets []Set) (ruleExpr, error)
+}
+
+type ruleCmp struct {
+	name  string
+	value string
+	equal bool
+	// quoted reports whether value was quoted in the rule.
+	quoted bool
+}
+
+func (e *ruleCmp) eval(lookup func(name string) (string, bool)) ruleValue {
+	v, ok := lookup(e.name)
+	if !ok {
+		return ruleUnknown
+	}
+	if (v == e.value) == e.equal {
+		return ruleTrue
+	}
+	return ruleFalse
+}
+
+func (e *ruleCmp) bind(sets []Set) (ruleExpr, error) {
+	for _, set := range sets {
+		if set.Name != e.name {
+			continue
+		}
+		v, err := typedValue(set.Type, e.value, e.quoted)
+		if err != nil {
+			return nil, err
+		}
+		v = normalizeValue(v)
+		for _, sv := range set.Values {
+			if normalizeValue(sv) == v {
+				return &ruleCmp{name: e.name, value: sv, equal: e.equal, quoted: true}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s %s", ErrRuleUnknownValue, e.name, e.value)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRuleUnknownSet, e.name)
+}
+
+type ruleNot struct {
+	x ruleExpr
+}
+
+func (e *ruleNot) eval(lookup func(name string) (string, bool)) ruleValue {
+	return e.x.eval(lookup).not()
+}
+
+func (e *ruleNot) bind(sets []Set) (ruleExpr, error) {
+	x, err := e.x.bind(sets)
+	if err != nil {
+		return nil, err
+	}
+	return &ruleNot{x: x}, nil
+}
+
+// ruleBinary is a && (and), || (or) or -> (implies) expression.
+type ruleBinary struct {
+	op   string
+	x, y ruleExpr
+}
+
+func (e *ruleBinary) eval(lookup func(name string) (string, bool)) ruleValue {
+	x, y := e.x.eval(lookup), e.y.eval(lookup)
+	if e.op == "->" {
+		x = x.not()
+	}
+	if e.op == "&&" {
+		switch {
+		case x == ruleFalse || y == ruleFalse:
+			return ruleFalse
+		case x == ruleTrue && y == ruleTrue:
+			return ruleTrue
+		}
+		return ruleUnknown
+	}
+	switch {
+	case x == ruleTrue || y == ruleTrue:
+		return ruleTrue
+	case x == ruleFalse && y == ruleFalse:
+		return ruleFalse
+	}
+	return ruleUnknown
+}
+
+func (e *ruleBinary) bind(sets []Set) (ruleExpr, error) {
+	x, err := e.x.bind(sets)
+	if err != nil {
+		return nil, err
+	}
+	y, err := e.y.bind(sets)
+	if err != nil {
+		return nil, err
+	}
+	return &ruleBinary{op: e.op, x: x, y: y}, nil
+}
+
+// ruleParser is a recursive descent parser of rule expressions.
+type ruleParser struct {
+	s string
+}
+
+func (p *ruleParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrRuleSyntax, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips leading spaces and returns what remains to parse.
+func (p *ruleParser) skipSpace() string {
+	p.s = strings.TrimLeftFunc(p.s, unicode.IsSpace)
+	return p.s
+}
+
+// accept consumes tok if it's next.
+func (p *ruleParser) accept(tok string) bool {
+	if strings.HasPrefix(p.skipSpace(), tok) {
+		p.s = p.s[len(tok):]
+		return true
+	}
+	return false
+}
+
+func (p *ruleParser) parseExpr() (ruleExpr, error) {
+	x, err := p.parseBinary("||")
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("->") {
+		y, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return &ruleBinary{op: "->", x: x, y: y}, nil
+	}
+	return x, nil
+}
+
+// parseBinary parses operands joined by op, which is || or &&.
+func (p *ruleParser) parseBinary(op string) (ruleExpr, error) {
+	parseOperand := p.parseUnary
+	if op == "||" {
+		parseOperand = func() (ruleExpr, error) { return p.parseBinary("&&") }
+	}
+	x, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(op) {
+		y, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		x = &ruleBinary{op: op, x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *ruleParser) parseUnary() (ruleExpr, error) {
+	if p.accept("!") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ruleNot{x: x}, nil
+	}
+	if p.accept("(") {
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("missing )")
+		}
+		return x, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *ruleParser) parseCmp() (ruleExpr, error) {
+	i := strings.IndexFunc(p.skipSpace(), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("=!()&|", r)
+	})
+	if i == -1 {
+		i = len(p.s)
+	}
+	if i == 0 {
+		if p.s == "" {
+			return nil, p.errorf("missing comparison")
+		}
+		return nil, p.errorf("unexpected %q", p.s)
+	}
+	cmp := &ruleCmp{name: p.s[:i]}
+	p.s = p.s[i:]
+	switch {
+	case p.accept("=="), p.accept("="):
+		cmp.equal = true
+	case p.accept("!="):
+	default:
+		return nil, p.errorf("missing == or != after %s", cmp.name)
+	}
+	value, quoted, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	cmp.value, cmp.quoted = value, quoted
+	return cmp, nil
+}
+
+// parseValue parses a value written like in a set, either quoted or bare, and reports whether it was quoted.
+// A bare value ends at a space, an operator or an unbalanced ).
+func (p *ruleParser) parseValue() (string, bool, error) {
+	s := p.skipSpace()
+	if strings.HasPrefix(s, `"`) {
+		q, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", false, p.errorf("invalid quoted value %s", s)
+		}
+		p.s = s[len(q):]
+		v, err := strconv.Unquote(q)
+		return v, true, err
+	}
+	var depth, i int
+	for i < len(s) {
+		c := s[i]
+		if c == ' ' || c == '\t' ||
+			strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||") || strings.HasPrefix(s[i:], "->") ||
+			(c == ')' && depth == 0) {
+			break
+		}
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		i++
+	}
+	if i == 0 {
+		return "", false, p.errorf("missing value")
+	}
+	p.s = s[i:]
+	return s[:i], false, nil
+}
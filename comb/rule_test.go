@@ -0,0 +1,197 @@
+package comb
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRuleAllows(t *testing.T) {
+	c := Combination{
+		{Name: "card", Value: "Heart"},
+		{Name: "figure", Value: "Jack"},
+		{Name: "call", Value: "rga()"},
+	}
+	tests := []struct {
+		rule  string
+		allow bool
+	}{
+		{rule: `!exclude card=="Heart" && figure=="Jack"`, allow: false},
+		{rule: `!exclude card==Heart&&figure==Queen`, allow: true},
+		{rule: `!exclude card=Tile || figure=Jack`, allow: false},
+		{rule: `!exclude !(card==Heart)`, allow: true},
+		{rule: `!require card==Heart -> figure!=Jack`, allow: false},
+		{rule: `!require: card==Tile -> figure!=Jack`, allow: true},
+		{rule: `require: card=Heart -> figure!=Jack`, allow: false},
+		{rule: `exclude : card=Tile`, allow: true},
+		{rule: `!require card==Heart && (figure==King || call==rga())`, allow: true},
+		{rule: `!require card==Heart -> figure==Jack -> call==x`, allow: false},
+		{rule: `!exclude figure=="Jack" && card=="\"Heart\""`, allow: true},
+	}
+	for _, test := range tests {
+		rule, err := ParseRule(test.rule)
+		if err != nil {
+			t.Errorf("%s: %v", test.rule, err)
+			continue
+		}
+		if allow := rule.Allows(c); allow != test.allow {
+			t.Errorf("%s: expected %v, got %v", test.rule, test.allow, allow)
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []string{
+		`!exclude`,
+		`!forbid card==Heart`,
+		`!exclude card`,
+		`!exclude card==`,
+		`!exclude (card==Heart`,
+		`!exclude card==Heart &&`,
+		`!exclude card=="Heart`,
+		`!exclude card==Heart figure==Jack`,
+	}
+	for _, text := range tests {
+		_, err := ParseRule(text)
+		if !errors.Is(err, ErrRuleSyntax) {
+			t.Errorf("%s: expected %v, got %v", text, ErrRuleSyntax, err)
+			continue
+		}
+		if rerr, ok := err.(*RuleError); !ok || rerr.Rule != text {
+			t.Errorf("%s: expected a *RuleError naming the rule, got %#v", text, err)
+		}
+	}
+}
+
+func TestParseSetsWithRules(t *testing.T) {
+	sets, rules, err := ParseSets(strings.NewReader(`card: Heart Tile
+figure: Jack Queen Joker
+!exclude figure==Joker
+require: card==Tile -> figure==Queen`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 || len(rules) != 2 {
+		t.Fatalf("expected 2 sets and 2 rules, got %d and %d", len(sets), len(rules))
+	}
+	combinations, err := New(sets, rules...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Heart Jack", "Heart Queen", "Tile Queen"}
+	if len(combinations) != len(expected) {
+		t.Fatalf("expected %d combinations, got %#v", len(expected), combinations)
+	}
+	for i, c := range combinations {
+		if s := c[0].Value + " " + c[1].Value; s != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], s)
+		}
+	}
+
+	_, _, err = ParseSets(strings.NewReader(`card: Heart Tile
+!exclude colour==Red`))
+	if !errors.Is(err, ErrRuleUnknownSet) {
+		t.Fatalf("expected %v, got %v", ErrRuleUnknownSet, err)
+	}
+	if !strings.Contains(err.Error(), "!exclude colour==Red") {
+		t.Errorf("expected the error to name the rule, got %v", err)
+	}
+}
+
+func TestCoveringWithRules(t *testing.T) {
+	tests := []struct {
+		sets  []Set
+		rules []string
+	}{
+		{
+			sets: []Set{
+				{Name: "os", Values: []string{"linux", "windows", "darwin"}},
+				{Name: "arch", Values: []string{"amd64", "arm64", "arm"}},
+				{Name: "cgo", Values: []string{"true", "false"}},
+			},
+			rules: []string{"!require os==windows -> arch!=arm", "!exclude os==darwin && cgo==false"},
+		},
+		{
+			// the first values of c and d can't be together: rows must be completed with c=q
+			sets: []Set{
+				{Name: "a", Values: []string{"1", "2"}},
+				{Name: "b", Values: []string{"x", "y"}},
+				{Name: "c", Values: []string{"p", "q"}},
+				{Name: "d", Values: []string{"p", "q"}},
+			},
+			rules: []string{"!exclude c==p && d==p", "!exclude c==p && d==q"},
+		},
+	}
+	for _, test := range tests {
+		var rules []Rule
+		for _, text := range test.rules {
+			rule, err := ParseRule(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rules = append(rules, rule)
+		}
+		combinations, err := NewCovering(test.sets, 2, rules...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range combinations {
+			if !allowed(rules, c) {
+				t.Errorf("%v is not allowed", c)
+			}
+		}
+		// every pair found in an allowed combination must be covered
+		all, err := New(test.sets, rules...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pairs := make(map[[2]string]bool)
+		for _, c := range combinations {
+			for i := range c {
+				for j := i + 1; j < len(c); j++ {
+					pairs[[2]string{c[i].Name + c[i].Value, c[j].Name + c[j].Value}] = true
+				}
+			}
+		}
+		for _, c := range all {
+			for i := range c {
+				for j := i + 1; j < len(c); j++ {
+					if !pairs[[2]string{c[i].Name + c[i].Value, c[j].Name + c[j].Value}] {
+						t.Errorf("%v: pair %v %v is not covered", test.rules, c[i], c[j])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRulesWithTypedSets(t *testing.T) {
+	sets, rules, err := ParseSets(strings.NewReader(`name string: alice bob carol
+f: "f(1, 2)" x
+!exclude name==alice
+!exclude name=="\"bob\""
+!require f=="f(1,2)"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	combinations, err := New(sets, rules...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Combination{{{Name: "name", Value: `"carol"`}, {Name: "f", Value: "f(1, 2)"}}}
+	if !reflect.DeepEqual(combinations, expected) {
+		t.Errorf("expected %#v, got %#v", expected, combinations)
+	}
+
+	for _, text := range []string{"!exclude name==dave", "!exclude name==alice || f==y"} {
+		rule, err := ParseRule(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := New(sets, rule); !errors.Is(err, ErrRuleUnknownValue) {
+			t.Errorf("%s: expected %v, got %v", text, ErrRuleUnknownValue, err)
+		}
+	}
+}
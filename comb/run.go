@@ -0,0 +1,73 @@
+package comb
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Run runs f as a subtest of t for each combination from sets allowed by rules, in the same order as New.
+//
+// Each subtest is named after the values of its combination, e.g card=Heart/figure=Jack,
+// with the values which are Go strings unquoted, so it can be run alone with go test -run 'Test/card=Heart/figure=Jack'.
+// If a subtest fails, the index of its combination is logged, to get it back with Unrank
+// or combination -index.
+//
+// Run fails t if the combinations can't be iterated, see NewIterator.
+func Run(t *testing.T, sets []Set, f func(t *testing.T, c Combination), rules ...Rule) {
+	t.Helper()
+	run(t, sets, f, false, rules)
+}
+
+// RunParallel is like Run, but each subtest calls t.Parallel before f.
+func RunParallel(t *testing.T, sets []Set, f func(t *testing.T, c Combination), rules ...Rule) {
+	t.Helper()
+	run(t, sets, f, true, rules)
+}
+
+func run(t *testing.T, sets []Set, f func(t *testing.T, c Combination), parallel bool, rules []Rule) {
+	t.Helper()
+	it, err := NewIterator(sets, rules...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		c, ok := it.Next()
+		if !ok {
+			return
+		}
+		i := it.Index()
+		t.Run(subtestName(c), func(t *testing.T) {
+			t.Cleanup(func() {
+				if t.Failed() {
+					t.Logf("combination index %d", i)
+				}
+			})
+			if parallel {
+				t.Parallel()
+			}
+			f(t, c)
+		})
+	}
+}
+
+// subtestName returns the name of the subtest of c run by Run: name=value for each element, separated by /.
+// Values which are Go strings are unquoted.
+func subtestName(c Combination) string {
+	var b strings.Builder
+	for i, e := range c {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		v := e.Value
+		if strings.HasPrefix(v, `"`) || strings.HasPrefix(v, "`") {
+			if s, err := strconv.Unquote(v); err == nil {
+				v = s
+			}
+		}
+		b.WriteString(e.Name)
+		b.WriteByte('=')
+		b.WriteString(v)
+	}
+	return b.String()
+}
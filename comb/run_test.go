@@ -0,0 +1,59 @@
+package comb
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart"`, "`Tile`"}},
+		{Name: "figure", Values: []string{`"Jack"`, "'Q'", "f(1)"}},
+	}
+	rule, err := ParseRule("!exclude figure == f(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	var combinations []Combination
+	Run(t, sets, func(t *testing.T, c Combination) {
+		names = append(names, t.Name()[strings.Index(t.Name(), "/")+1:])
+		combinations = append(combinations, c)
+	}, rule)
+
+	expectedNames := []string{
+		"card=Heart/figure=Jack",
+		"card=Heart/figure='Q'",
+		"card=Tile/figure=Jack",
+		"card=Tile/figure='Q'",
+	}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("expected names %q, got %q", expectedNames, names)
+	}
+	expected, err := New(sets, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(combinations, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, combinations)
+	}
+}
+
+func TestRunParallel(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		visits int
+	)
+	t.Run("group", func(t *testing.T) {
+		RunParallel(t, rankSets, func(t *testing.T, c Combination) {
+			mu.Lock()
+			defer mu.Unlock()
+			visits++
+		})
+	})
+	if visits != 12 {
+		t.Errorf("expected 12 subtests, got %d", visits)
+	}
+}
@@ -0,0 +1,148 @@
+package comb
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Sample picks n distinct combinations from sets at random, leaving out those not allowed by rules,
+// and returns their indices in the order of New.
+// If there are no more than n allowed combinations, the indices of all of them are returned.
+//
+// Combinations are drawn by index, so that only the picked ones are created.
+// If rules leave too few of them to pick n after a bounded number of draws,
+// the allowed combinations are counted instead, without creating them (see CountRules),
+// and n ranks among them are drawn and turned into indices.
+// Each has the same chance to be picked, and the same seed always gives the same indices.
+//
+// It returns the indices or an error, ErrSetNoValues if one of the sets provided has no values,
+// an *OverflowError if there are too many combinations to index them or a *RuleError if a rule refers to an unknown set.
+func Sample(sets []Set, n int, seed int64, rules ...Rule) ([]int, error) {
+	total, err := numCombinations(sets)
+	if err != nil {
+		return nil, err
+	}
+	rules, err = checkRules(sets, rules)
+	if err != nil {
+		return nil, err
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	var indices []int
+	if n < total {
+		indices, err = drawIndices(sets, rules, n, total, rnd)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(indices) < n {
+		indices = pickIndices(sets, rules, n, rnd)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+// maxDrawsPerIndex bounds the number of draws made by drawIndices for each index to pick.
+const maxDrawsPerIndex = 16
+
+// drawIndices picks up to n distinct indices of allowed combinations from sets by drawing them at random.
+// It gives up after a number of draws proportional to n, returning the indices picked so far.
+func drawIndices(sets []Set, rules []Rule, n, total int, rnd *rand.Rand) ([]int, error) {
+	seen := make(map[int]bool)
+	var indices []int
+	for draws := 0; len(indices) < n && draws < maxDrawsPerIndex*n+64; draws++ {
+		i := int(rnd.Int63n(int64(total)))
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		c, err := Unrank(sets, i)
+		if err != nil {
+			return nil, err
+		}
+		if allowed(rules, c) {
+			indices = append(indices, i)
+		}
+	}
+	return indices, nil
+}
+
+// pickIndices picks n distinct indices of allowed combinations from sets by drawing their rank among them,
+// or returns all of them if there are no more than n.
+func pickIndices(sets []Set, rules []Rule, n int, rnd *rand.Rand) []int {
+	allowed := int(countAllowed(sets, rules).Int64())
+	var ranks []int
+	if allowed <= n {
+		for k := 0; k < allowed; k++ {
+			ranks = append(ranks, k)
+		}
+	} else {
+		// Floyd's algorithm: n draws for n distinct ranks.
+		seen := make(map[int]bool, n)
+		for j := allowed - n; j < allowed; j++ {
+			k := int(rnd.Int63n(int64(j + 1)))
+			if seen[k] {
+				k = j
+			}
+			seen[k] = true
+			ranks = append(ranks, k)
+		}
+	}
+	indices := make([]int, len(ranks))
+	for i, k := range ranks {
+		indices[i] = allowedIndex(sets, rules, k)
+	}
+	return indices
+}
+
+// allowedIndex returns the index in the order of New of the combination from sets
+// which is the k-th one allowed by rules, starting at 0.
+// The value of each set is found by counting the allowed combinations having each of its values.
+func allowedIndex(sets []Set, rules []Rule, k int) int {
+	row := make([]int, len(sets))
+	for s := range row {
+		row[s] = -1
+	}
+	var i int
+	for s, set := range sets {
+		for v := range set.Values {
+			row[s] = v
+			n := int(countRow(sets, rules, row).Int64())
+			if k < n {
+				break
+			}
+			k -= n
+		}
+		i = i*len(set.Values) + row[s]
+	}
+	return i
+}
+
+// IndicesIterator yields the combinations from sets at given indices, such as those picked by Sample.
+type IndicesIterator struct {
+	sets    []Set
+	indices []int
+	index   int
+}
+
+// NewIndicesIterator creates an iterator over the combinations from sets at indices.
+func NewIndicesIterator(sets []Set, indices []int) *IndicesIterator {
+	return &IndicesIterator{sets: sets, indices: indices, index: -1}
+}
+
+// Next implements Iterator.
+func (it *IndicesIterator) Next() (Combination, bool) {
+	if len(it.indices) == 0 {
+		return nil, false
+	}
+	it.index, it.indices = it.indices[0], it.indices[1:]
+	c, err := Unrank(it.sets, it.index)
+	if err != nil {
+		return nil, false
+	}
+	return c, true
+}
+
+// Index implements IndexedIterator.
+func (it *IndicesIterator) Index() int {
+	return it.index
+}
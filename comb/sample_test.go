@@ -0,0 +1,196 @@
+package comb
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	indices, err := Sample(rankSets, 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 5 {
+		t.Fatalf("expected 5 indices, got %v", indices)
+	}
+	if !sort.IntsAreSorted(indices) {
+		t.Errorf("expected sorted indices, got %v", indices)
+	}
+	for i := 1; i < len(indices); i++ {
+		if indices[i] == indices[i-1] {
+			t.Errorf("expected distinct indices, got %v", indices)
+		}
+	}
+	again, err := Sample(rankSets, 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(indices, again) {
+		t.Errorf("expected the same indices for the same seed, got %v and %v", indices, again)
+	}
+}
+
+func TestSampleAll(t *testing.T) {
+	indices, err := Sample(rankSets, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if !reflect.DeepEqual(indices, expected) {
+		t.Errorf("expected %v, got %v", expected, indices)
+	}
+}
+
+func TestSampleWithRules(t *testing.T) {
+	rule, err := ParseRule(`!require S1=="\"X\"" && I3==42`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indices, err := Sample(rankSets, 3, 7, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{1, 4}
+	if !reflect.DeepEqual(indices, expected) {
+		t.Errorf("expected %v, got %v", expected, indices)
+	}
+}
+
+func TestSampleFewAllowed(t *testing.T) {
+	var sets []Set
+	for _, name := range []string{"s1", "s2", "s3", "s4"} {
+		set := Set{Name: name}
+		for v := 0; v < 100; v++ {
+			set.Values = append(set.Values, strconv.Itoa(v))
+		}
+		sets = append(sets, set)
+	}
+	rule, err := ParseRule("!require s1==0 && s2==0 && s3==0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indices, err := Sample(sets, 200, 1, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected []int
+	for i := 0; i < 100; i++ {
+		expected = append(expected, i)
+	}
+	if !reflect.DeepEqual(indices, expected) {
+		t.Errorf("expected %v, got %v", expected, indices)
+	}
+
+	indices, err = Sample(sets, 50, 1, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 50 {
+		t.Fatalf("expected 50 indices, got %v", indices)
+	}
+	for k, i := range indices {
+		if i >= 100 || k > 0 && i <= indices[k-1] {
+			t.Fatalf("expected distinct sorted indices of allowed combinations, got %v", indices)
+		}
+	}
+}
+
+func TestSampleSelectiveRule(t *testing.T) {
+	var sets []Set
+	for _, name := range []string{"a", "b", "c", "d"} {
+		set := Set{Name: name}
+		for v := 1; v <= 1000; v++ {
+			set.Values = append(set.Values, strconv.Itoa(v))
+		}
+		sets = append(sets, set)
+	}
+	rule, err := ParseRule("!require a==1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indices, err := Sample(sets, 2, 3, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 2 || indices[0] >= indices[1] {
+		t.Fatalf("expected 2 distinct sorted indices, got %v", indices)
+	}
+	for _, i := range indices {
+		c, err := Unrank(sets, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rule.Allows(c) {
+			t.Errorf("expected combinations allowed by %s, got %v", rule.Text, c)
+		}
+	}
+}
+
+func TestAllowedIndex(t *testing.T) {
+	rule, err := ParseRule(`!exclude S2=="\"v\"" -> I3==42`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := checkRules(rankSets, []Rule{rule})
+	if err != nil {
+		t.Fatal(err)
+	}
+	it, err := NewIterator(rankSets, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; ; k++ {
+		if _, ok := it.Next(); !ok {
+			break
+		}
+		if i := allowedIndex(rankSets, rules, k); i != it.Index() {
+			t.Errorf("rank %d: expected index %d, got %d", k, it.Index(), i)
+		}
+	}
+}
+
+func TestWriteIndexedIterator(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart"`, `"Tile"`}},
+		{Name: "figure", Values: []string{`"Jack"`, `"Queen"`}},
+	}
+	rule, err := ParseRule(`!exclude figure=="\"Queen\""`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		it     IndexedIterator
+		output string
+	}{
+		{
+			it: NewIndicesIterator(sets, []int{1, 2}),
+			output: `{card: "Heart", figure: "Queen"}, // 1
+{card: "Tile", figure: "Jack"}, // 2
+`,
+		},
+		{
+			it: func() IndexedIterator {
+				it, err := NewIterator(sets, rule)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return it
+			}(),
+			output: `{card: "Heart", figure: "Jack"}, // 0
+{card: "Tile", figure: "Jack"}, // 2
+`,
+		},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteIndexedIterator(&buf, test.it); err != nil {
+			t.Fatal(err)
+		}
+		if output := buf.String(); output != test.output {
+			t.Errorf("expected:\n%#v\ngot:\n%#v", test.output, output)
+		}
+	}
+}
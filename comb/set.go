@@ -0,0 +1,388 @@
+package comb
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseError records an error and the position in the sets where it occurred.
+type ParseError struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	var pos string
+	if e.File != "" {
+		pos += e.File + ":"
+	}
+	if e.Line > 0 {
+		pos += strconv.Itoa(e.Line) + ":"
+	}
+	if e.Column > 0 {
+		pos += strconv.Itoa(e.Column) + ":"
+	}
+	if pos == "" {
+		return e.Err.Error()
+	}
+	return pos + " " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// withFile sets the file of err to name if it's a *ParseError.
+func withFile(err error, name string) error {
+	if perr, ok := err.(*ParseError); ok {
+		perr.File = name
+	}
+	return err
+}
+
+// ParseSets parses sets and rules, one per line.
+//
+// Blank lines are skipped, and comments start with # or // at the beginning of a value, outside of quotes,
+// up to the end of the line. A line ending with \ continues on the next one.
+//
+// Syntax errors are returned as a *ParseError.
+func ParseSets(r io.Reader) ([]Set, []Rule, error) {
+	var (
+		sets      []Set
+		rules     []Rule
+		rulesLine []int
+		text      string
+		segments  []lineSegment
+	)
+	parseLine := func() error {
+		if isRule(text) {
+			rule, err := ParseRule(text)
+			if err != nil {
+				return &ParseError{Line: segments[0].line, Column: segments[0].column, Err: err}
+			}
+			rules = append(rules, rule)
+			rulesLine = append(rulesLine, segments[0].line)
+			return nil
+		}
+		var set Set
+		if err := set.UnmarshalText([]byte(text)); err != nil {
+			if perr, ok := err.(*ParseError); ok {
+				perr.Line, perr.Column = position(segments, perr.Column)
+			}
+			return err
+		}
+		sets = append(sets, set)
+		return nil
+	}
+
+	bufsrc := bufio.NewScanner(r)
+	bufsrc.Split(bufio.ScanLines)
+	for n := 1; bufsrc.Scan(); n++ {
+		line := strings.TrimRightFunc(stripComment(bufsrc.Text()), unicode.IsSpace)
+		trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+		continued := strings.HasSuffix(trimmed, `\`)
+		if continued {
+			trimmed = strings.TrimRightFunc(trimmed[:len(trimmed)-1], unicode.IsSpace)
+		}
+		if trimmed != "" {
+			segments = append(segments, lineSegment{
+				offset: len(text),
+				line:   n,
+				column: len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace)) + 1,
+			})
+			if continued {
+				trimmed += " "
+			}
+			text += trimmed
+		}
+		if continued || strings.TrimSpace(text) == "" {
+			continue
+		}
+		text = strings.TrimSpace(text)
+		if err := parseLine(); err != nil {
+			return nil, nil, err
+		}
+		text, segments = "", nil
+	}
+	if err := bufsrc.Err(); err != nil {
+		return nil, nil, err
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		if err := parseLine(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i := range rules {
+		bound, err := checkRules(sets, rules[i:i+1])
+		if err != nil {
+			return nil, nil, &ParseError{Line: rulesLine[i], Column: 1, Err: err}
+		}
+		rules[i] = bound[0]
+	}
+	return sets, rules, nil
+}
+
+// lineSegment is the part of a line of sets starting at offset in the text to parse.
+type lineSegment struct {
+	offset int
+	line   int
+	column int
+}
+
+// position returns the line and column of the given column in the text made of segments.
+func position(segments []lineSegment, column int) (int, int) {
+	if len(segments) == 0 {
+		return 0, column
+	}
+	seg := segments[0]
+	for _, s := range segments[1:] {
+		if s.offset > column-1 {
+			break
+		}
+		seg = s
+	}
+	return seg.line, column - 1 - seg.offset + seg.column
+}
+
+// stripComment returns line without its comment, if any.
+func stripComment(line string) string {
+	var quoted, escaped bool
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case (c == '#' || strings.HasPrefix(line[i:], "//")) && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Set represents a named grouping of values (a set).
+//
+// Type is the optional Go type of the values, written after the name:
+//
+//	status int: http.StatusOK http.StatusNotFound
+type Set struct {
+	Name   string
+	Type   string
+	Values []string
+}
+
+// MarshalText implements TextMarshaler.
+func (s Set) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	if s.Name == "" {
+		return nil, ErrSetInvalidName
+	}
+	name := s.Name
+	if s.Type != "" {
+		name += " " + s.Type
+	}
+	if _, err := io.WriteString(&buf, name+": "); err != nil {
+		return nil, err
+	}
+	for _, val := range s.Values {
+		if _, err := io.WriteString(&buf, strconv.Quote(val)+" "); err != nil {
+			return nil, err
+		}
+	}
+	b := buf.Bytes()
+	return b[:len(b)-1], nil
+}
+
+// UnmarshalText implements TextUnmarshaler.
+//
+// Unquoted values are expanded: first their braces, such as user-{admin,guest} (see expandBraces),
+// then the ranges, such as 1..10, 0..100..10 or 'a'..'f' (see expandRange).
+//
+// If the set has a type, e.g port int: 80 443, its values are checked against it (see typedValue),
+// and with the type string, unquoted values are Go strings, e.g name string: alice is "alice".
+//
+// Syntax errors are returned as a *ParseError, with the column in text where they occurred.
+func (s *Set) UnmarshalText(text []byte) error {
+	r := bufio.NewReader(bytes.NewReader(text))
+	name, err := r.ReadString(':')
+	if err == io.EOF {
+		return &ParseError{Column: len(text) + 1, Err: ErrSetNoColon}
+	}
+	if err != nil {
+		return err
+	}
+	s.Name, s.Type = splitSetName(name[:len(name)-1])
+	if s.Name == "" {
+		return &ParseError{Column: 1, Err: ErrSetInvalidName}
+	}
+	offset := len(name)
+	defer func() {
+		if s.Values == nil {
+			s.Values = []string{}
+		}
+	}()
+	runne, _, err := r.ReadRune()
+	if err == io.EOF {
+		// We don't have any values
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if runne != ' ' {
+		if err := r.UnreadRune(); err != nil {
+			return err
+		}
+	} else {
+		offset++
+	}
+
+	var (
+		column int
+		quoted bool
+		blank  bool
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := SetValuesSplitFn(data, atEOF)
+		if perr, ok := err.(*ParseError); ok {
+			perr.Column += offset
+		}
+		if token != nil {
+			lead := len(data) - len(bytes.TrimLeft(data, " "))
+			// only trailing spaces are left
+			blank = lead == len(data)
+			column, quoted = offset+lead+1, !blank && data[lead] == '"'
+		}
+		offset += advance
+		return advance, token, err
+	})
+	for scanner.Scan() {
+		if blank {
+			continue
+		}
+		if quoted {
+			v, err := typedValue(s.Type, scanner.Text(), true)
+			if err != nil {
+				return &ParseError{Column: column, Err: err}
+			}
+			s.Values = append(s.Values, v)
+			continue
+		}
+		words, err := expandBraces(scanner.Text())
+		if err != nil {
+			return &ParseError{Column: column, Err: err}
+		}
+		for _, word := range words {
+			values, err := expandRange(word)
+			if err != nil {
+				return &ParseError{Column: column, Err: err}
+			}
+			if values == nil {
+				values = []string{word}
+			}
+			for _, v := range values {
+				if v, err = typedValue(s.Type, v, false); err != nil {
+					return &ParseError{Column: column, Err: err}
+				}
+				s.Values = append(s.Values, v)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return err
+}
+
+// splitSetName splits the name of a set and its optional type, e.g "status int".
+func splitSetName(s string) (name string, typ string) {
+	name = strings.TrimSpace(s)
+	if i := strings.IndexByte(name, ' '); i != -1 {
+		name, typ = name[:i], strings.TrimSpace(name[i+1:])
+	}
+	return name, typ
+}
+
+// SetValuesSplitFn is a scanner func to split values of a set.
+//
+// Syntax errors are returned as a *ParseError, with the column in data where they occurred.
+var SetValuesSplitFn = bufio.SplitFunc(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	// eat all leading spaces or "," if any
+	for _, b := range data {
+		if b != ' ' {
+			break
+		}
+		advance++
+	}
+	start := advance
+	if len(data) > start && data[start] == '"' {
+		var ignoreNextQuote bool
+		closingQuoteIdx := -1
+		for i, b := range data[start+1:] {
+			if b == '\\' && !ignoreNextQuote {
+				ignoreNextQuote = true
+				continue
+			}
+			if b == '\\' && ignoreNextQuote {
+				ignoreNextQuote = false
+				continue
+			}
+			if b != '"' {
+				ignoreNextQuote = false
+				continue
+			}
+			if ignoreNextQuote {
+				ignoreNextQuote = false
+				continue
+			}
+			closingQuoteIdx = i
+			break
+		}
+		if closingQuoteIdx == -1 {
+			if atEOF {
+				err = &ParseError{Column: start + 1, Err: ErrValueNoClosingQuote}
+				return
+			}
+			// ask more data to get the closing quote
+			return 0, nil, nil
+		}
+		advance += len(data[start : (start+1)+(closingQuoteIdx+1)])
+		var s string
+		s, err = strconv.Unquote(string(data[start : (start+1)+(closingQuoteIdx+1)]))
+		if err != nil {
+			err = &ParseError{Column: start + 1, Err: err}
+		}
+		token = []byte(s)
+		return
+	}
+	// no opening quote. let's read until ' ' or EOF
+	spaceIdx := bytes.IndexByte(data[start:], ' ')
+	if spaceIdx == -1 && !atEOF {
+		// Not at EOF and no comma, so we have an incomplete token
+		return 0, nil, nil
+	} else if spaceIdx == -1 && atEOF {
+		// that's our complete last token
+		advance += len(data[start:])
+		token = data[start:]
+		return
+	}
+	advance += len(data[start : start+spaceIdx])
+	token = data[start : start+spaceIdx]
+	return
+})
@@ -0,0 +1,267 @@
+package comb
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode"
+)
+
+// TestMarshalUnmarshalSet tests a roundtrip {enc,dec}oding of a Set.
+func TestMarshalUnmarshalSet(t *testing.T) {
+	if err := quick.Check(func(name string, values []string) bool {
+		if name == "" {
+			set := Set{Name: name, Values: values}
+			_, err := set.MarshalText()
+			if err == nil {
+				t.Error("expected non-nil error, got nil")
+				return false
+			}
+			return true
+		}
+		// cleanup name
+		var buf bytes.Buffer
+		for _, ruune := range name {
+			// Go identifier
+			if unicode.IsLetter(ruune) || unicode.IsDigit(ruune) {
+				buf.WriteRune(ruune)
+			}
+		}
+
+		iset := Set{Name: buf.String(), Values: values}
+		if iset.Name == "" {
+			// name had no valid rune, just put one
+			iset.Name = "a"
+		}
+		b, err := iset.MarshalText()
+		if err != nil {
+			t.Error(err)
+			return false
+		}
+		var oset Set
+		if err := oset.UnmarshalText(b); err != nil {
+			t.Error(err)
+			return false
+		}
+		if !reflect.DeepEqual(iset, oset) {
+			t.Errorf("expected %#v, got %#v", iset, oset)
+			return false
+		}
+		return true
+	}, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnmarshalSetTrailingSpaces(t *testing.T) {
+	for _, text := range []string{"a: x ", "a: x   ", `a: x "" `} {
+		var s Set
+		if err := s.UnmarshalText([]byte(text)); err != nil {
+			t.Errorf("%q: %v", text, err)
+			continue
+		}
+		expected := []string{"x"}
+		if strings.Contains(text, `""`) {
+			expected = append(expected, "")
+		}
+		if !reflect.DeepEqual(s.Values, expected) {
+			t.Errorf("%q: expected %q, got %q", text, expected, s.Values)
+		}
+	}
+}
+
+func TestParseSets(t *testing.T) {
+	tests := []struct {
+		input string
+		sets  []Set
+	}{
+		{
+			input: `card: Heart Tile Clover Pike
+figure: Jack Queen King`,
+			sets: []Set{
+				{Name: "card", Values: []string{"Heart", "Tile", "Clover", "Pike"}},
+				{Name: "figure", Values: []string{"Jack", "Queen", "King"}},
+			},
+		},
+		{
+			input: `card: "Heart Rouge" "Tile Rouge" "Clover Noir" "Pike Noir"
+figure: Jack Queen King`,
+			sets: []Set{
+				{Name: "card", Values: []string{"Heart Rouge", "Tile Rouge", "Clover Noir", "Pike Noir"}},
+				{Name: "figure", Values: []string{"Jack", "Queen", "King"}},
+			},
+		},
+		{
+			input: `card: "\"Heart Rouge\"" "Tile Rouge" "Clover Noir" "Pike Noir"
+figure: Jack Queen King`,
+			sets: []Set{
+				{Name: "card", Values: []string{"\"Heart Rouge\"", "Tile Rouge", "Clover Noir", "Pike Noir"}},
+				{Name: "figure", Values: []string{"Jack", "Queen", "King"}},
+			},
+		},
+		{
+			input: `card: "\"Heart\nRouge\"" "Tile Rouge" "Clover\nNoir" "Pike Noir"
+figure: Jack Queen King`,
+			sets: []Set{
+				{Name: "card", Values: []string{"\"Heart\nRouge\"", "Tile Rouge", "Clover\nNoir", "Pike Noir"}},
+				{Name: "figure", Values: []string{"Jack", "Queen", "King"}},
+			},
+		},
+		{
+			input: `card:
+figure: Jack Queen King`,
+			sets: []Set{
+				{Name: "card", Values: []string{}},
+				{Name: "figure", Values: []string{"Jack", "Queen", "King"}},
+			},
+		},
+		{
+			// trailing spaces
+			input: `card: Heart Tile Clover Pike   
+figure: Jack Queen King`,
+			sets: []Set{
+				{Name: "card", Values: []string{"Heart", "Tile", "Clover", "Pike"}},
+				{Name: "figure", Values: []string{"Jack", "Queen", "King"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		sets, _, err := ParseSets(strings.NewReader(test.input))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(sets, test.sets) {
+			t.Errorf("expected:\n%#v\ngot:\n%#v", test.sets, sets)
+		}
+	}
+}
+
+func TestParseSetsWithType(t *testing.T) {
+	sets, _, err := ParseSets(strings.NewReader(`status int: http.StatusOK http.StatusNotFound
+body []byte: nil
+card: Heart`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Set{
+		{Name: "status", Type: "int", Values: []string{"http.StatusOK", "http.StatusNotFound"}},
+		{Name: "body", Type: "[]byte", Values: []string{"nil"}},
+		{Name: "card", Values: []string{"Heart"}},
+	}
+	if !reflect.DeepEqual(sets, expected) {
+		t.Fatalf("expected:\n%#v\ngot:\n%#v", expected, sets)
+	}
+	b, err := sets[0].MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != `status int: "http.StatusOK" "http.StatusNotFound"` {
+		t.Errorf("unexpected marshaled set %s", s)
+	}
+}
+
+func TestParseSetsTypedValues(t *testing.T) {
+	sets, _, err := ParseSets(strings.NewReader(`port int: 80 0x1BB 'a' 1e3 http.StatusOK
+name string: alice bob{1..2} "\"carol\"" "http.MethodGet"
+ratio float32: 1 0.5
+small int8: -128 127
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Set{
+		{Name: "port", Type: "int", Values: []string{"80", "0x1BB", "'a'", "1e3", "http.StatusOK"}},
+		{Name: "name", Type: "string", Values: []string{`"alice"`, `"bob1"`, `"bob2"`, `"carol"`, "http.MethodGet"}},
+		{Name: "ratio", Type: "float32", Values: []string{"1", "0.5"}},
+		{Name: "small", Type: "int8", Values: []string{"-128", "127"}},
+	}
+	if !reflect.DeepEqual(sets, expected) {
+		t.Fatalf("expected:\n%#v\ngot:\n%#v", expected, sets)
+	}
+
+	var again Set
+	b, err := sets[1].MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := again.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, sets[1]) {
+		t.Errorf("expected %#v, got %#v", sets[1], again)
+	}
+}
+
+func TestParseSetsCommentsAndContinuations(t *testing.T) {
+	input := `# cards of a deck
+card: Heart Tile \
+      Clover Pike // the 4 colours
+
+// figures
+figure: Jack Queen "King # not a comment" \
+	url:http://x #1
+!exclude card==Heart # no heart jack
+`
+	sets, rules, err := ParseSets(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Set{
+		{Name: "card", Values: []string{"Heart", "Tile", "Clover", "Pike"}},
+		{Name: "figure", Values: []string{"Jack", "Queen", "King # not a comment", "url:http://x"}},
+	}
+	if !reflect.DeepEqual(sets, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, sets)
+	}
+	if len(rules) != 1 || rules[0].Text != "!exclude card==Heart" {
+		t.Errorf("expected the rule without its comment, got %#v", rules)
+	}
+}
+
+func TestParseSetsErrors(t *testing.T) {
+	tests := []struct {
+		input        string
+		err          error
+		line, column int
+	}{
+		{input: `card: Heart "Tile`, err: ErrValueNoClosingQuote, line: 1, column: 13},
+		{input: "# cards\ncard: a \\\n   b \"c", err: ErrValueNoClosingQuote, line: 3, column: 6},
+		{input: "card: a\n  : x", err: ErrSetInvalidName, line: 2, column: 3},
+		{input: "card: a\n\nfigure Jack", err: ErrSetNoColon, line: 3, column: 12},
+		{input: `card: "a\z"`, err: strconv.ErrSyntax, line: 1, column: 7},
+		{input: "card: a\n  !exclude card==", err: ErrRuleSyntax, line: 2, column: 3},
+		{input: "!exclude figure==a\ncard: a", err: ErrRuleUnknownSet, line: 1, column: 1},
+		{input: `port int: 80 "\"x\""`, err: ErrValueType, line: 1, column: 14},
+		{input: "port uint8: 1..300", err: ErrValueType, line: 1, column: 13},
+		{input: "ok bool: true 1", err: ErrValueType, line: 1, column: 15},
+		{input: `f int: "f(1"`, err: ErrValueSyntax, line: 1, column: 8},
+	}
+	for _, test := range tests {
+		_, _, err := ParseSets(strings.NewReader(test.input))
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: expected %v, got %v", test.input, test.err, err)
+			continue
+		}
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q: expected a *ParseError, got %#v", test.input, err)
+			continue
+		}
+		if perr.Line != test.line || perr.Column != test.column {
+			t.Errorf("%q: expected %d:%d, got %d:%d", test.input, test.line, test.column, perr.Line, perr.Column)
+		}
+	}
+}
+
+func TestParseErrorString(t *testing.T) {
+	_, _, err := ParseSets(strings.NewReader("card: a\nfigure: \"Jack"))
+	err = withFile(err, "cards.sets")
+	if s := err.Error(); s != "cards.sets:2:9: set value has no closing quote" {
+		t.Errorf("unexpected error %s", s)
+	}
+}
@@ -0,0 +1,111 @@
+package comb
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteMarkdown writes the combinations yielded by it from sets to w as a GitHub-flavored Markdown table,
+// with the set names as columns, written even if there are no combinations.
+//
+// If numbered is true, the first column holds the index of each combination:
+// the one given by it if it's an IndexedIterator, or else its position, starting at 0.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteMarkdown(w io.Writer, sets []Set, it Iterator, numbered bool) error {
+	return writeRows(w, sets, it, numbered, func(cells []string, header bool) string {
+		for i, cell := range cells {
+			cells[i] = strings.Replace(cell, "|", `\|`, -1)
+		}
+		row := "| " + strings.Join(cells, " | ") + " |\n"
+		if header {
+			row += strings.Repeat("| --- ", len(cells)) + "|\n"
+		}
+		return row
+	})
+}
+
+// WriteHTML writes the combinations yielded by it from sets to w as an HTML table,
+// with the set names as column headers and escaped cells.
+//
+// If numbered is true, the first column holds the index of each combination, as with WriteMarkdown.
+//
+// It returns an error if an error occurs when writing to w.
+func WriteHTML(w io.Writer, sets []Set, it Iterator, numbered bool) error {
+	if _, err := io.WriteString(w, "<table>\n"); err != nil {
+		return err
+	}
+	var rows int
+	err := writeRows(w, sets, it, numbered, func(cells []string, header bool) string {
+		tag := "td"
+		if header {
+			tag = "th"
+		}
+		var row string
+		switch {
+		case header:
+			row = "<thead>\n"
+		case rows == 0:
+			row = "<tbody>\n"
+		}
+		if !header {
+			rows++
+		}
+		row += "<tr>"
+		for _, cell := range cells {
+			row += fmt.Sprintf("<%s>%s</%s>", tag, html.EscapeString(cell), tag)
+		}
+		row += "</tr>\n"
+		if header {
+			row += "</thead>\n"
+		}
+		return row
+	})
+	if err != nil {
+		return err
+	}
+	end := "</table>\n"
+	if rows > 0 {
+		end = "</tbody>\n" + end
+	}
+	_, err = io.WriteString(w, end)
+	return err
+}
+
+// writeRows writes the header and the rows of a table built by format from the combinations yielded by it.
+// The header is the names of sets, preceded by # if numbered is true.
+func writeRows(w io.Writer, sets []Set, it Iterator, numbered bool, format func(cells []string, header bool) string) error {
+	var cells []string
+	if numbered {
+		cells = append(cells, "#")
+	}
+	for _, set := range sets {
+		cells = append(cells, set.Name)
+	}
+	if _, err := io.WriteString(w, format(cells, true)); err != nil {
+		return err
+	}
+	for n := 0; ; n++ {
+		c, ok := it.Next()
+		if !ok {
+			return nil
+		}
+		cells = cells[:0]
+		if numbered {
+			i := n
+			if iit, ok := it.(IndexedIterator); ok {
+				i = iit.Index()
+			}
+			cells = append(cells, strconv.Itoa(i))
+		}
+		for _, e := range c {
+			cells = append(cells, e.Value)
+		}
+		if _, err := io.WriteString(w, format(cells, false)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package comb
+
+import (
+	"bytes"
+	"testing"
+)
+
+var tableSets = []Set{
+	{Name: "card", Values: []string{`"Heart"`, `"<Tile>"`}},
+	{Name: "op", Values: []string{"a|b"}},
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	tests := []struct {
+		it       Iterator
+		numbered bool
+		output   string
+	}{
+		{
+			it: SliceIterator([]Combination{
+				{{Name: "card", Value: `"Heart"`}, {Name: "op", Value: "a|b"}},
+			}),
+			output: "| card | op |\n" +
+				"| --- | --- |\n" +
+				"| \"Heart\" | a\\|b |\n",
+		},
+		{
+			it: SliceIterator(nil),
+			output: "| card | op |\n" +
+				"| --- | --- |\n",
+		},
+		{
+			it:       NewIndicesIterator(tableSets, []int{1}),
+			numbered: true,
+			output: "| # | card | op |\n" +
+				"| --- | --- | --- |\n" +
+				"| 1 | \"<Tile>\" | a\\|b |\n",
+		},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteMarkdown(&buf, tableSets, test.it, test.numbered); err != nil {
+			t.Fatal(err)
+		}
+		if output := buf.String(); output != test.output {
+			t.Errorf("expected:\n%s\ngot:\n%s", test.output, output)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	it, err := NewIterator(tableSets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, tableSets, it, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<table>
+<thead>
+<tr><th>#</th><th>card</th><th>op</th></tr>
+</thead>
+<tbody>
+<tr><td>0</td><td>&#34;Heart&#34;</td><td>a|b</td></tr>
+<tr><td>1</td><td>&#34;&lt;Tile&gt;&#34;</td><td>a|b</td></tr>
+</tbody>
+</table>
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+
+	buf.Reset()
+	if err := WriteHTML(&buf, tableSets, SliceIterator(nil), false); err != nil {
+		t.Fatal(err)
+	}
+	expected = `<table>
+<thead>
+<tr><th>card</th><th>op</th></tr>
+</thead>
+</table>
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected a table with only a header, got %s", output)
+	}
+}
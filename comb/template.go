@@ -0,0 +1,209 @@
+package comb
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// TemplateFuncs are the functions available to the templates parsed by ParseTemplate:
+//
+//	quote    quotes a string as a Go string literal
+//	unquote  unquotes a Go string literal, and leaves other values as is
+//	camel    writes a string in camelCase, e.g "Heart Red" as heartRed
+//	snake    writes a string in snake_case, e.g "Heart Red" as heart_red
+var TemplateFuncs = template.FuncMap{
+	"quote":   strconv.Quote,
+	"unquote": unquoteValue,
+	"camel":   camelCase,
+	"snake":   snakeCase,
+}
+
+// TemplateTable is the data of the header and footer templates.
+type TemplateTable struct {
+	// Names are the set names.
+	Names []string
+	// Total is the number of combinations.
+	Total int
+}
+
+// TemplateRow is the data of the template executed for each combination.
+type TemplateRow struct {
+	Combination Combination
+	// Index is the index of the combination given by the iterator if it's an IndexedIterator,
+	// or else its position, starting at 0.
+	Index int
+	// Total is the number of combinations.
+	Total int
+	// First and Last report whether the combination is the first or the last one.
+	First, Last bool
+}
+
+// Get returns the value of the element named name, or "" if there is none.
+func (r TemplateRow) Get(name string) string {
+	for _, e := range r.Combination {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+// ParseTemplate parses a template for WriteTemplate, with TemplateFuncs.
+//
+// The template is executed for each combination with a TemplateRow, e.g:
+//
+//	{{define "header"}}var cards = map[string]int{
+//	{{end}}	{{.Get "card" | quote}}: {{.Index}},
+//	{{define "footer"}}}
+//	{{end}}
+//
+// The optional templates named header and footer are executed once, before and after the combinations,
+// with a TemplateTable.
+func ParseTemplate(name string, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs).Parse(text)
+}
+
+// WriteTemplate writes to w the combinations yielded by it from sets with t, as described by ParseTemplate.
+//
+// Combinations are written as they are read, but for the one following the combination being written,
+// for it to know whether it is the last one. Their total is counted without keeping them
+// for the iterators of this package; other iterators are read in full before the first combination is written.
+//
+// It returns an error if t fails to execute or if an error occurs when writing to w.
+func WriteTemplate(w io.Writer, t *template.Template, sets []Set, it Iterator) error {
+	var n int
+	next := func() (TemplateRow, bool) {
+		c, ok := it.Next()
+		if !ok {
+			return TemplateRow{}, false
+		}
+		row := TemplateRow{Combination: c, Index: n, First: n == 0}
+		if iit, ok := it.(IndexedIterator); ok {
+			row.Index = iit.Index()
+		}
+		n++
+		return row, true
+	}
+	total, ok := remaining(it)
+	if !ok {
+		var rows []TemplateRow
+		for row, ok := next(); ok; row, ok = next() {
+			rows = append(rows, row)
+		}
+		total = len(rows)
+		next = func() (TemplateRow, bool) {
+			if len(rows) == 0 {
+				return TemplateRow{}, false
+			}
+			row := rows[0]
+			rows = rows[1:]
+			return row, true
+		}
+	}
+
+	table := TemplateTable{Total: total}
+	for _, set := range sets {
+		table.Names = append(table.Names, set.Name)
+	}
+	row, ok := next()
+	if header := t.Lookup("header"); header != nil {
+		if err := header.Execute(w, table); err != nil {
+			return err
+		}
+	}
+	for ok {
+		following, more := next()
+		row.Total, row.Last = total, !more
+		if err := t.Execute(w, row); err != nil {
+			return err
+		}
+		row, ok = following, more
+	}
+	if footer := t.Lookup("footer"); footer != nil {
+		if err := footer.Execute(w, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remaining returns the number of combinations it has left to yield,
+// and reports whether it could be counted without moving it.
+func remaining(it Iterator) (int, bool) {
+	switch it := it.(type) {
+	case *ProductIterator:
+		if len(it.rules) == 0 {
+			return it.len - it.next, true
+		}
+		// count the combinations allowed by rules with a copy
+		cp := *it
+		cp.indices = append([]int(nil), it.indices...)
+		var n int
+		for {
+			if _, ok := cp.Next(); !ok {
+				return n, true
+			}
+			n++
+		}
+	case *IndicesIterator:
+		return len(it.indices), true
+	case *sliceIterator:
+		return len(*it), true
+	}
+	return 0, false
+}
+
+// words splits s in words, at characters which aren't letters or digits
+// and before upper case letters following lower case ones.
+func words(s string) []string {
+	var (
+		ws   []string
+		word []rune
+		prev rune
+	)
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				ws = append(ws, string(word))
+				word = nil
+			}
+			prev = r
+			continue
+		}
+		if unicode.IsUpper(r) && unicode.IsLower(prev) && len(word) > 0 {
+			ws = append(ws, string(word))
+			word = nil
+		}
+		word = append(word, r)
+		prev = r
+	}
+	if len(word) > 0 {
+		ws = append(ws, string(word))
+	}
+	return ws
+}
+
+func camelCase(s string) string {
+	ws := words(s)
+	for i, w := range ws {
+		w = strings.ToLower(w)
+		if i > 0 {
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			w = string(r)
+		}
+		ws[i] = w
+	}
+	return strings.Join(ws, "")
+}
+
+func snakeCase(s string) string {
+	ws := words(s)
+	for i, w := range ws {
+		ws[i] = strings.ToLower(w)
+	}
+	return strings.Join(ws, "_")
+}
@@ -0,0 +1,110 @@
+package comb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart Red"`, `"Tile"`}},
+		{Name: "n", Values: []string{"1", "2"}},
+	}
+	tmpl, err := ParseTemplate("test", `{{define "header"}}// {{.Total}} {{range .Names}}{{.}} {{end}}
+var values = []string{
+{{end}}	{{if .First}}/* first */ {{end}}{{.Get "card" | unquote | snake | quote}}, // {{.Index}}/{{.Total}} {{.Get "n"}}{{if .Last}} last{{end}}
+{{define "footer"}}}
+{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, tmpl, sets, NewIndicesIterator(sets, []int{0, 3})); err != nil {
+		t.Fatal(err)
+	}
+	expected := `// 2 card n 
+var values = []string{
+	/* first */ "heart_red", // 0/2 1
+	"tile", // 3/2 2 last
+}
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+
+	buf.Reset()
+	if err := WriteTemplate(&buf, tmpl, sets, SliceIterator(nil)); err != nil {
+		t.Fatal(err)
+	}
+	expected = `// 0 card n 
+var values = []string{
+}
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// onlyIterator hides the type of an Iterator.
+type onlyIterator struct {
+	Iterator
+}
+
+func TestWriteTemplateTotal(t *testing.T) {
+	sets := []Set{
+		{Name: "card", Values: []string{`"Heart"`, `"Tile"`}},
+		{Name: "n", Values: []string{"1", "2", "3"}},
+	}
+	rule, err := ParseRule("!exclude n==2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplate("test", `{{define "header"}}{{.Total}}:{{end}} {{.Index}}/{{.Total}}{{if .Last}}.{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newIterator := func() *ProductIterator {
+		it, err := NewIterator(sets, rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return it
+	}
+	tests := []struct {
+		it     Iterator
+		output string
+	}{
+		{it: newIterator(), output: "4: 0/4 2/4 3/4 5/4."},
+		{it: NewIndicesIterator(sets, []int{0, 2, 3, 5}), output: "4: 0/4 2/4 3/4 5/4."},
+		// read in full, with positions as indices
+		{it: onlyIterator{newIterator()}, output: "4: 0/4 1/4 2/4 3/4."},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteTemplate(&buf, tmpl, sets, test.it); err != nil {
+			t.Fatal(err)
+		}
+		if output := buf.String(); output != test.output {
+			t.Errorf("%T: expected %q, got %q", test.it, test.output, output)
+		}
+	}
+}
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		s, camel, snake string
+	}{
+		{s: "Heart Red", camel: "heartRed", snake: "heart_red"},
+		{s: "figureName", camel: "figureName", snake: "figure_name"},
+		{s: "HTTP-status_code", camel: "httpStatusCode", snake: "http_status_code"},
+		{s: "  ", camel: "", snake: ""},
+	}
+	for _, test := range tests {
+		if s := camelCase(test.s); s != test.camel {
+			t.Errorf("%q: expected %q, got %q", test.s, test.camel, s)
+		}
+		if s := snakeCase(test.s); s != test.snake {
+			t.Errorf("%q: expected %q, got %q", test.s, test.snake, s)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package comb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	beginMarker = "// combination:begin"
+	endMarker   = "// combination:end"
+)
+
+// ErrMarkerNoEnd represents an error when a begin marker has no matching end marker.
+var ErrMarkerNoEnd = errors.New("combination:begin has no combination:end")
+
+// ErrMarkerNoSets represents an error when a begin marker doesn't name a sets file.
+var ErrMarkerNoSets = errors.New("combination:begin has no sets")
+
+// Regenerate replaces the rows between each pair of markers in the Go source src
+// with the combinations from the sets file named by the begin marker:
+//
+//	tests := []struct {
+//		card   string
+//		figure string
+//	}{
+//		// combination:begin sets=cards.sets
+//		{card: "Heart", figure: "Jack"},
+//		// combination:end
+//	}
+//
+// The rows are indented like the begin marker. A relative sets file is found from the directory of filename,
+// which is the name of the source, used in errors.
+// Regenerating an up-to-date source leaves it unchanged.
+//
+// It returns the new source, or an error if the markers or the sets file are invalid.
+func Regenerate(filename string, src []byte) ([]byte, error) {
+	var (
+		out    bytes.Buffer
+		inside bool
+		begin  int
+	)
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	for i, line := range lines {
+		text := strings.TrimSpace(string(line))
+		switch {
+		case strings.HasPrefix(text, beginMarker):
+			if inside {
+				return nil, fmt.Errorf("%s:%d: %w", filename, begin+1, ErrMarkerNoEnd)
+			}
+			inside, begin = true, i
+			out.Write(line)
+			indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+			rows, err := regenerateRows(strings.TrimPrefix(text, beginMarker), filepath.Dir(filename))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filename, i+1, err)
+			}
+			for _, row := range rows {
+				out.Write(indent)
+				out.WriteString(row)
+				out.WriteString("\n")
+			}
+		case strings.HasPrefix(text, endMarker):
+			inside = false
+			out.Write(line)
+		case !inside:
+			out.Write(line)
+		}
+	}
+	if inside {
+		return nil, fmt.Errorf("%s:%d: %w", filename, begin+1, ErrMarkerNoEnd)
+	}
+	return out.Bytes(), nil
+}
+
+// regenerateRows returns the rows for the arguments of a begin marker.
+func regenerateRows(args string, dir string) ([]string, error) {
+	var setsPath string
+	for _, arg := range strings.Fields(args) {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] != "sets" {
+			return nil, fmt.Errorf("combination:begin has an unknown argument %q", arg)
+		}
+		setsPath = kv[1]
+	}
+	if setsPath == "" {
+		return nil, ErrMarkerNoSets
+	}
+	if !filepath.IsAbs(setsPath) {
+		setsPath = filepath.Join(dir, setsPath)
+	}
+	f, err := os.Open(setsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	sets, rules, err := ParseSetsFormat(InputFormat(setsPath), f)
+	if err != nil {
+		return nil, withFile(err, setsPath)
+	}
+	it, err := NewIterator(sets, rules...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := WriteIterator(&buf, it); err != nil {
+		return nil, err
+	}
+	var rows []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		rows = append(rows, scanner.Text())
+	}
+	return rows, scanner.Err()
+}
+
+// UpdateFile regenerates the rows between the markers of the Go file at path, as with Regenerate.
+// The file is only written if its rows have changed.
+func UpdateFile(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	out, err := Regenerate(path, src)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(src, out) {
+		return nil
+	}
+	return os.WriteFile(path, out, 0644)
+}
@@ -0,0 +1,92 @@
+package comb
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegenerate(t *testing.T) {
+	src := `package cards
+
+var tests = []struct {
+	card   string
+	figure string
+}{
+	// combination:begin sets=cards.sets
+	{card: "Clover", figure: "King"},
+	// combination:end
+	{card: "Pike", figure: "Ace"},
+}
+`
+	expected := `package cards
+
+var tests = []struct {
+	card   string
+	figure string
+}{
+	// combination:begin sets=cards.sets
+	{card: "Heart", figure: "Jack"},
+	{card: "Heart", figure: "Queen"},
+	{card: "Tile", figure: "Jack"},
+	// combination:end
+	{card: "Pike", figure: "Ace"},
+}
+`
+	filename := filepath.Join("testdata", "cards_test.go")
+	out, err := Regenerate(filename, []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+	again, err := Regenerate(filename, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != expected {
+		t.Errorf("expected regenerating to be a no-op, got:\n%s", again)
+	}
+}
+
+func TestRegenerateErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err error
+		msg string
+	}{
+		{
+			src: "// combination:begin sets=cards.sets\n",
+			err: ErrMarkerNoEnd,
+			msg: "x_test.go:1: ",
+		},
+		{
+			src: "\n// combination:begin\n// combination:end\n",
+			err: ErrMarkerNoSets,
+			msg: "x_test.go:2: ",
+		},
+		{
+			src: "// combination:begin sets=cards.sets strength=2\n// combination:end\n",
+			msg: "unknown argument",
+		},
+		{
+			src: "// combination:begin sets=nope.sets\n// combination:end\n",
+			msg: "nope.sets",
+		},
+	}
+	for _, test := range tests {
+		_, err := Regenerate(filepath.Join("testdata", "x_test.go"), []byte(test.src))
+		if err == nil {
+			t.Errorf("%q: expected a non-nil error, got nil", test.src)
+			continue
+		}
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%q: expected %v, got %v", test.src, test.err, err)
+		}
+		if !strings.Contains(err.Error(), test.msg) {
+			t.Errorf("%q: expected %q in error, got %v", test.src, test.msg, err)
+		}
+	}
+}
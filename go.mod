@@ -0,0 +1,3 @@
+module github.com/vincent-petithory/combination
+
+go 1.23